@@ -0,0 +1,322 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"palco/internal/database/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// boardColumn is a column of the board: a status of the project's workflow,
+// or a priority when the project has no workflow
+type boardColumn struct {
+	title    string
+	status   models.Status
+	priority int
+	cards    []int // Indexes into m.tasks of the top level tasks in the column
+}
+
+// boardColumns sorts the selected project's top level tasks into columns,
+// keeping the task list's order within each column
+func boardColumns(m Model) []boardColumn {
+	var columns []boardColumn
+	if len(m.workflow) > 0 {
+		for _, status := range m.workflow {
+			columns = append(columns, boardColumn{title: status.Name, status: status})
+		}
+	} else {
+		for priority := models.PriorityUrgent; priority >= models.PriorityNone; priority-- {
+			columns = append(columns, boardColumn{title: getPriorityText(priority), priority: priority})
+		}
+	}
+
+	for i, task := range m.tasks {
+		if m.taskDepths[i] > 0 {
+			continue
+		}
+
+		column := 0
+		if len(m.workflow) > 0 {
+			status := m.workflow.Of(task)
+			for j := range columns {
+				if columns[j].status.ID == status.ID {
+					column = j
+				}
+			}
+		} else {
+			column = models.PriorityUrgent - task.Priority
+		}
+		columns[column].cards = append(columns[column].cards, i)
+	}
+	return columns
+}
+
+// selectedCard finds the selected task on the board. ok is false when the
+// selection is not a card, which the board avoids by selecting the card of
+// a subtask instead.
+func selectedCard(m Model, columns []boardColumn) (column, row int, ok bool) {
+	for c, col := range columns {
+		for r, i := range col.cards {
+			if i == m.selectedTaskIndex {
+				return c, r, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// cardOf returns the index in m.tasks of the card holding the task at
+// index i: the task itself when it is top level, or its top level ancestor
+func cardOf(m Model, i int) int {
+	for i > 0 && i < len(m.taskDepths) && m.taskDepths[i] > 0 {
+		i--
+	}
+	return i
+}
+
+// toggleBoard switches between the grid and the board. The board works on
+// the tasks section, starting on the card holding a selected subtask.
+func (m Model) toggleBoard() (tea.Model, tea.Cmd) {
+	m.board = !m.board
+	if !m.board {
+		return m, nil
+	}
+
+	m.activeSection = 1
+	if card := cardOf(m, m.selectedTaskIndex); len(m.tasks) > 0 && card != m.selectedTaskIndex {
+		m.selectedTaskIndex = card
+		return m, tea.Batch(m.loadNotes, m.loadDependencies)
+	}
+	return m, nil
+}
+
+// updateBoard handles the keys the board uses differently from the grid:
+// arrows select cards, h/l move the selected card to the previous or next
+// column and j/k move it within its column. Other keys act on the selected
+// task like in the tasks section; ok is false for those.
+func (m Model) updateBoard(msg tea.KeyMsg) (model tea.Model, cmd tea.Cmd, ok bool) {
+	columns := boardColumns(m)
+	column, row, selected := selectedCard(m, columns)
+
+	switch msg.String() {
+	case "v":
+		model, cmd = m.toggleBoard()
+		return model, cmd, true
+
+	// Sections other than tasks are not on the board
+	case "tab", "shift+tab", "1", "2", "3", "4", "5":
+		return m, nil, true
+
+	case "left", "right":
+		step := 1
+		if msg.String() == "left" {
+			step = -1
+		}
+		if !selected {
+			return m, nil, true
+		}
+
+		// Take the card at the same height in the nearest column that has any
+		for c := column + step; c >= 0 && c < len(columns); c += step {
+			if cards := columns[c].cards; len(cards) > 0 {
+				m.selectedTaskIndex = cards[min(row, len(cards)-1)]
+				return m, tea.Batch(m.loadNotes, m.loadDependencies), true
+			}
+		}
+		return m, nil, true
+
+	case "up", "down":
+		next := row + 1
+		if msg.String() == "up" {
+			next = row - 1
+		}
+		if !selected || next < 0 || next >= len(columns[column].cards) {
+			return m, nil, true
+		}
+		m.selectedTaskIndex = columns[column].cards[next]
+		return m, tea.Batch(m.loadNotes, m.loadDependencies), true
+
+	case "h", "l":
+		step := 1
+		if msg.String() == "h" {
+			step = -1
+		}
+		if !selected || column+step < 0 || column+step >= len(columns) {
+			return m, nil, true
+		}
+		target := columns[column+step]
+		return m, func() tea.Msg { return m.moveCard(target) }, true
+
+	case "k", "j":
+		cards := columns[column].cards
+		next := row + 1
+		if msg.String() == "k" {
+			next = row - 1
+		}
+		if !selected || next < 0 || next >= len(cards) {
+			return m, nil, true
+		}
+
+		// Reorder counts places among all top level tasks, so the card
+		// skips past those in other columns
+		offset := topLevelIndex(m, cards[next]) - topLevelIndex(m, cards[row])
+		return m, func() tea.Msg { return m.reorderTask(offset) }, true
+
+	// Toggling keeps the card selected on the board
+	case " ", "enter":
+		if selected {
+			m.selectTaskID = m.tasks[m.selectedTaskIndex].ID
+		}
+	}
+
+	return m, nil, false
+}
+
+// moveCard moves the selected task to another column, setting its status,
+// or its priority on a board without a workflow
+func (m Model) moveCard(target boardColumn) tea.Msg {
+	if len(m.tasks) == 0 || m.selectedTaskIndex >= len(m.tasks) {
+		return nil
+	}
+
+	task := m.tasks[m.selectedTaskIndex]
+	if len(m.workflow) > 0 {
+		return m.setTaskStatus(task, target.status)
+	}
+
+	updatedTask, err := m.TaskRepo.Update(task.ID, task.Title, target.priority, task.Dates(), task.Repeat())
+	if err != nil {
+		return fail(err)
+	}
+	return taskMovedMsg{task: *updatedTask}
+}
+
+// topLevelIndex returns the place of the top level task at index i of
+// m.tasks among the project's top level tasks
+func topLevelIndex(m Model, i int) int {
+	n := 0
+	for j := 0; j < i; j++ {
+		if m.taskDepths[j] == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Board shows the selected project's tasks as columns by status, an
+// alternate layout to Grid for planning
+func Board(m Model) string {
+	height := m.height - 1 // Status bar
+
+	if len(m.projects) == 0 || len(m.tasks) == 0 {
+		message := "No project selected"
+		if len(m.projects) > 0 {
+			message = "No tasks found"
+		}
+		return Section(true).Width(m.width - 2).Height(height - 2).Render(
+			lipgloss.NewStyle().Foreground(subtle).Padding(1).Render(message),
+		)
+	}
+
+	columns := boardColumns(m)
+	selectedColumn, selectedRow, _ := selectedCard(m, columns)
+
+	columnWidth := m.width / len(columns)
+	cardWidth := max(columnWidth-4, 8)
+
+	// Cards take four lines with their border; the header two more
+	limit := max((height-4)/4, 1)
+
+	rendered := make([]string, len(columns))
+	for c, column := range columns {
+		parts := []string{listHeader(fmt.Sprintf("%s (%d)", column.title, len(column.cards)))}
+
+		// Keep the selected card in view when the column is taller than the screen
+		start := 0
+		if c == selectedColumn && selectedRow >= limit {
+			start = selectedRow - limit + 1
+		}
+		end := min(start+limit, len(column.cards))
+
+		for r := start; r < end; r++ {
+			i := column.cards[r]
+			parts = append(parts, renderCard(m, i, cardWidth, c == selectedColumn && r == selectedRow))
+		}
+		if hidden := len(column.cards) - (end - start); hidden > 0 {
+			parts = append(parts, lipgloss.NewStyle().Foreground(subtle).Render(fmt.Sprintf("  +%d more", hidden)))
+		}
+
+		rendered[c] = Section(c == selectedColumn).
+			Width(columnWidth - 2).
+			Height(height - 2).
+			Render(lipgloss.JoinVertical(lipgloss.Left, parts...))
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
+}
+
+// boardName names the board after its project for the status bar
+func boardName(m Model) string {
+	if len(m.projects) == 0 {
+		return "Board"
+	}
+	return "Board · " + m.projects[m.selectedProjectIndex].Name
+}
+
+// renderCard renders the task at index i of m.tasks as a card with its
+// title, priority and subtask progress
+func renderCard(m Model, i int, width int, selected bool) string {
+	task := m.tasks[i]
+
+	title := task.Title
+	if task.Recurrence.Valid {
+		title += " ↻"
+	}
+	if len(title) > width {
+		title = title[:max(width-3, 0)] + "..."
+	}
+
+	titleStyle := lipgloss.NewStyle()
+	if task.Completed {
+		titleStyle = titleStyle.Strikethrough(true).Foreground(blockedColor)
+	} else if m.blockedTasks[task.ID] {
+		titleStyle = titleStyle.Foreground(blockedColor)
+	}
+
+	// Count the subtasks at every level below the task
+	done, total := 0, 0
+	for j := i + 1; j < len(m.tasks) && m.taskDepths[j] > m.taskDepths[i]; j++ {
+		total++
+		if m.tasks[j].Completed {
+			done++
+		}
+	}
+
+	var meta []string
+	if task.Priority != models.PriorityNone {
+		meta = append(meta, getPriorityText(task.Priority))
+	}
+	if total > 0 {
+		meta = append(meta, fmt.Sprintf("%d/%d subtasks", done, total))
+	}
+	if m.blockedTasks[task.ID] && !task.Completed {
+		meta = append(meta, "blocked")
+	}
+
+	borderColor := subtle
+	if selected {
+		borderColor = highlight
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Width(width).
+		Render(lipgloss.JoinVertical(lipgloss.Left,
+			titleStyle.Render(title),
+			lipgloss.NewStyle().Foreground(blockedColor).Render(strings.Join(meta, " · ")),
+		))
+}
@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"testing"
+
+	"palco/internal/database/models"
+)
+
+func TestBoardSelectsCards(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, m Model, ids map[string]int64) Model
+		want string
+	}{
+		{"toggling with a subtask selected", func(t *testing.T, m Model, ids map[string]int64) Model {
+			parentID := ids["Paint"]
+			if _, err := m.TaskRepo.Move(ids["Rollers"], m.projects[0].ID, &parentID); err != nil {
+				t.Fatal(err)
+			}
+			m = send(t, m, m.loadTasks())
+			m.selectedTaskIndex = taskIndex(m, ids["Rollers"])
+			return press(t, m, "v")
+		}, "Paint"},
+		{"indenting a card", func(t *testing.T, m Model, ids map[string]int64) Model {
+			m = press(t, m, "v")
+			m.selectedTaskIndex = taskIndex(m, ids["Rollers"])
+			return press(t, m, ">")
+		}, "Paint"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t)
+			project, err := m.ProjectRepo.Create("Home", nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := make(map[string]int64)
+			for _, title := range []string{"Sweep", "Paint", "Rollers"} {
+				task, err := m.TaskRepo.Create(project.ID, nil, title, nil, 0, models.TaskDates{}, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				ids[title] = task.ID
+			}
+
+			m = send(t, m, m.loadProjects())
+			m.activeSection = 1
+			m = tt.run(t, m, ids)
+
+			if !m.board {
+				t.Fatal("not on the board")
+			}
+			if _, _, ok := selectedCard(m, boardColumns(m)); !ok {
+				t.Fatalf("selection %d is not a card", m.selectedTaskIndex)
+			}
+			if got := m.tasks[m.selectedTaskIndex].Title; got != tt.want {
+				t.Errorf("selected card = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// taskIndex returns the index of a task in m.tasks
+func taskIndex(m Model, id int64) int {
+	for i, task := range m.tasks {
+		if task.ID == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"palco/internal/database/models"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// undoWindow is how long a deletion can be undone with u
+const undoWindow = time.Minute
+
+// deletePreview describes what a pending delete will remove
+type deletePreview struct {
+	project bool // true for a project, false for a task
+	id      int64
+	name    string
+	tasks   int // Tasks, or subtasks when deleting a task
+	notes   int
+}
+
+// label names the item being deleted, e.g. `project "Website"`
+func (p deletePreview) label() string {
+	if p.project {
+		return fmt.Sprintf("project %q", p.name)
+	}
+	return fmt.Sprintf("task %q", p.name)
+}
+
+// undoEntry is the last deletion, which u takes back out of the trash
+type undoEntry struct {
+	id      int
+	deleted deletePreview
+}
+
+// trashKind is the kind of trash item the preview's item becomes
+func (p deletePreview) trashKind() string {
+	if p.project {
+		return models.TrashProject
+	}
+	return models.TrashTask
+}
+
+// newDeletePreview counts what deleting the root of snapshot removes
+func newDeletePreview(snapshot *models.Workspace) deletePreview {
+	if len(snapshot.Projects) > 0 {
+		project := snapshot.Projects[0]
+		return deletePreview{
+			project: true,
+			id:      project.ID,
+			name:    project.Name,
+			tasks:   len(snapshot.Tasks),
+			notes:   len(snapshot.Notes),
+		}
+	}
+
+	// The root is the task whose parent is outside the subtree. It is not
+	// always the oldest, since an older task can be indented under a newer one.
+	inSubtree := make(map[int64]bool, len(snapshot.Tasks))
+	for _, task := range snapshot.Tasks {
+		inSubtree[task.ID] = true
+	}
+	task := snapshot.Tasks[0]
+	for _, t := range snapshot.Tasks {
+		if !t.ParentTaskID.Valid || !inSubtree[t.ParentTaskID.Int64] {
+			task = t
+			break
+		}
+	}
+	return deletePreview{
+		id:    task.ID,
+		name:  task.Title,
+		tasks: len(snapshot.Tasks) - 1,
+		notes: len(snapshot.Notes),
+	}
+}
+
+// plural formats a count with its noun, e.g. "1 task" or "3 tasks"
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// RenderConfirmDelete asks before deleting a project or task
+func RenderConfirmDelete(m Model) string {
+	if m.pendingDelete == nil {
+		return ""
+	}
+	p := m.pendingDelete
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(errorColor).
+		MarginBottom(1)
+
+	keyStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(highlight)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(subtle).
+		MarginTop(1)
+
+	taskNoun := "task"
+	if !p.project {
+		taskNoun = "subtask"
+	}
+
+	parts := []string{
+		titleStyle.Render("Delete " + p.label() + "?"),
+		wrapText(fmt.Sprintf("This also deletes %s and %s.", plural(p.tasks, taskNoun), plural(p.notes, "note")), 50),
+		helpStyle.Render(fmt.Sprintf("It goes to the trash (T). You can undo this with u for %d seconds.", int(undoWindow.Seconds()))),
+		"",
+		keyStyle.Render("y") + " Delete   " + keyStyle.Render("n/Esc") + " Cancel",
+	}
+	if errorLine := renderInlineError(m, 50); errorLine != "" {
+		parts = append(parts, errorLine)
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(errorColor).
+		Padding(2, 4).
+		Width(60).
+		Render(lipgloss.JoinVertical(lipgloss.Left, parts...))
+
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		box,
+	)
+}
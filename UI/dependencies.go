@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"palco/internal/database/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// noticeTimeout is how long a notice stays in the status bar
+const noticeTimeout = 5 * time.Second
+
+// blockedColor dims tasks that wait on open tasks
+var blockedColor = lipgloss.AdaptiveColor{Light: "#969B86", Dark: "#696969"}
+
+type dependenciesLoadedMsg struct {
+	blockers   []models.Task
+	dependents []models.Task
+}
+
+type blockedTasksLoadedMsg struct {
+	blocked map[int64]bool
+}
+
+type dependencyChangedMsg struct {
+	linked  bool
+	task    models.Task
+	blocker models.Task
+}
+
+// clearNoticeMsg dismisses the status bar notice if it is still the one
+// with the given id
+type clearNoticeMsg struct {
+	id int
+}
+
+// loadDependencies loads what the selected task waits on and what waits on it
+func (m Model) loadDependencies() tea.Msg {
+	if len(m.tasks) == 0 || m.selectedTaskIndex >= len(m.tasks) {
+		return dependenciesLoadedMsg{}
+	}
+
+	taskID := m.tasks[m.selectedTaskIndex].ID
+	blockers, err := m.DependencyRepo.GetBlockers(taskID)
+	if err != nil {
+		return fail(err)
+	}
+	dependents, err := m.DependencyRepo.GetDependents(taskID)
+	if err != nil {
+		return fail(err)
+	}
+
+	return dependenciesLoadedMsg{blockers: blockers, dependents: dependents}
+}
+
+// loadBlockedTasks loads which tasks of the selected project are blocked
+func (m Model) loadBlockedTasks() tea.Msg {
+	if len(m.projects) == 0 || m.selectedProjectIndex >= len(m.projects) {
+		return blockedTasksLoadedMsg{}
+	}
+
+	blocked, err := m.DependencyRepo.GetBlockedIDs(m.projects[m.selectedProjectIndex].ID)
+	if err != nil {
+		return fail(err)
+	}
+	return blockedTasksLoadedMsg{blocked: blocked}
+}
+
+// markBlocker handles b in the tasks section. The first press marks the
+// selected task as a blocker; pressing it again on another task, in any
+// project, makes that task wait on the blocker, or unlinks them when it
+// already does. Pressing it on the blocker itself clears the mark.
+func (m *Model) markBlocker() tea.Cmd {
+	if len(m.tasks) == 0 || m.selectedTaskIndex >= len(m.tasks) {
+		return nil
+	}
+
+	task := m.tasks[m.selectedTaskIndex]
+	switch {
+	case m.blocker == nil:
+		m.blocker = &task
+		return nil
+	case m.blocker.ID == task.ID:
+		m.blocker = nil
+		return nil
+	}
+
+	blocker := *m.blocker
+	return func() tea.Msg {
+		linked, err := m.DependencyRepo.Exists(task.ID, blocker.ID)
+		if err != nil {
+			return fail(err)
+		}
+
+		if linked {
+			err = m.DependencyRepo.Remove(task.ID, blocker.ID)
+		} else {
+			err = m.DependencyRepo.Add(task.ID, blocker.ID)
+		}
+		if err != nil {
+			return fail(err)
+		}
+
+		return dependencyChangedMsg{linked: !linked, task: task, blocker: blocker}
+	}
+}
+
+// showNotice shows text in the status bar and schedules its dismissal
+func (m *Model) showNotice(text string) tea.Cmd {
+	m.noticeID++
+	m.statusNotice = text
+
+	id := m.noticeID
+	return tea.Tick(noticeTimeout, func(time.Time) tea.Msg {
+		return clearNoticeMsg{id: id}
+	})
+}
+
+// unblockedNotice describes the tasks a completion freed up
+func unblockedNotice(unblocked []models.Task) string {
+	titles := make([]string, len(unblocked))
+	for i, task := range unblocked {
+		titles[i] = task.Title
+	}
+	return "Unblocked: " + strings.Join(titles, ", ")
+}
+
+// renderDependencies lists the selected task's blockers and the tasks it
+// blocks for the details panel
+func renderDependencies(m Model) []string {
+	labelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(special).
+		MarginTop(1)
+
+	var parts []string
+	for _, group := range []struct {
+		label string
+		tasks []models.Task
+	}{
+		{"Blocked by", m.blockers},
+		{"Blocks", m.dependents},
+	} {
+		if len(group.tasks) == 0 {
+			continue
+		}
+
+		parts = append(parts, labelStyle.Render(fmt.Sprintf("%s (%d):", group.label, len(group.tasks))))
+		for _, task := range group.tasks {
+			parts = append(parts, "  "+dependencyLine(m, task))
+		}
+	}
+	return parts
+}
+
+// dependencyLine renders a linked task with its status, naming its project
+// when it is not the selected one
+func dependencyLine(m Model, task models.Task) string {
+	status := "[ ]"
+	if task.Completed {
+		status = "[✓]"
+	}
+
+	line := status + " " + task.Title
+	if len(m.projects) > 0 && task.ProjectID.Int64 != m.projects[m.selectedProjectIndex].ID {
+		line += " (" + projectName(m, task.ProjectID.Int64) + ")"
+	}
+	return line
+}
+
+// projectName returns the name of a loaded project, or its ID when the
+// project list is filtered
+func projectName(m Model, id int64) string {
+	for _, project := range m.projects {
+		if project.ID == id {
+			return project.Name
+		}
+	}
+	return fmt.Sprintf("project #%d", id)
+}
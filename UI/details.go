@@ -3,6 +3,10 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
+
+	"palco/internal/database/models"
+	"palco/internal/recurrence"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -76,6 +80,9 @@ func renderProjectDetails(m Model) string {
 			Foreground(special)
 
 		dateStr := project.DueDate.Time.Format("2006-01-02")
+		if days, ok := project.DaysUntilDue(time.Now()); ok && !project.Archived {
+			dateStr += " " + lipgloss.NewStyle().Foreground(dueColor(days)).Render("("+dueText(days)+")")
+		}
 		parts = append(parts, labelStyle.Render("Due Date: ")+dateStr)
 	}
 
@@ -125,16 +132,7 @@ func renderTaskDetails(m Model) string {
 	parts = append(parts, titleStyle.Render(task.Title))
 
 	// Status
-	statusLabel := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(special).
-		Render("Status: ")
-
-	status := "[ ] Incomplete"
-	if task.Completed {
-		status = "[✓] Completed"
-	}
-	parts = append(parts, statusLabel+status)
+	parts = append(parts, renderStatusDetails(m, task)...)
 
 	// Priority
 	priorityLabel := lipgloss.NewStyle().
@@ -145,6 +143,31 @@ func renderTaskDetails(m Model) string {
 	priorityText := getPriorityText(task.Priority)
 	parts = append(parts, priorityLabel+priorityText)
 
+	// Dates
+	dateLabel := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(special)
+
+	if task.DueAt.Valid {
+		dateStr := models.FormatTaskDate(task.DueAt.Time)
+		if days, ok := task.DaysUntilDue(time.Now()); ok && !task.Completed {
+			dateStr += " " + lipgloss.NewStyle().Foreground(dueColor(days)).Render("("+dueText(days)+")")
+		}
+		parts = append(parts, dateLabel.Render("Due: ")+dateStr)
+	}
+	if task.StartAt.Valid {
+		parts = append(parts, dateLabel.Render("Start: ")+models.FormatTaskDate(task.StartAt.Time))
+	}
+	if task.ScheduledFor.Valid {
+		parts = append(parts, dateLabel.Render("Scheduled: ")+models.FormatTaskDate(task.ScheduledFor.Time))
+	}
+	if rule, err := recurrence.Parse(task.Recurrence.String); task.Recurrence.Valid && err == nil {
+		parts = append(parts, dateLabel.Render("Repeats: ")+rule.Describe())
+	}
+
+	// Dependencies
+	parts = append(parts, renderDependencies(m)...)
+
 	// Description (from notes)
 	if len(m.notes) > 0 {
 		for _, note := range m.notes {
@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func Drafts(m Model) string {
+	col1Width := int(float64(m.width) * 0.40)
+	col2Width := int(float64(m.width) * 0.40)
+	// Take the remaining width, 6 is for the 6 borders
+	col3Width := m.width - col1Width - col2Width - 6
+
+	// Build content
+	var content string
+	if len(m.drafts) == 0 {
+		content = lipgloss.NewStyle().
+			Foreground(subtle).
+			Padding(1).
+			Render("Inbox zero. Press n to capture a draft")
+	} else {
+		content = renderDraftList(m, col3Width)
+	}
+
+	return Section(m.activeSection == 4).Width(col3Width).Height(m.height - 3).Render(
+		lipgloss.JoinVertical(lipgloss.Left,
+			listHeader(fmt.Sprintf("Drafts [5] (%d)", len(m.drafts))),
+			content,
+		),
+	)
+}
+
+func renderDraftList(m Model, width int) string {
+	// Leave room for the cursor and padding
+	maxLen := width - 4
+	if maxLen < 5 {
+		maxLen = 5
+	}
+
+	items := make([]string, len(m.drafts))
+	for i, draft := range m.drafts {
+		cursor := " "
+		if i == m.selectedDraftIndex && m.activeSection == 4 {
+			cursor = ">"
+		}
+
+		title := draft.Title()
+		if strings.Contains(strings.TrimSpace(draft.Content), "\n") {
+			title += " …"
+		}
+		if len(title) > maxLen {
+			title = title[:maxLen-3] + "..."
+		}
+
+		items[i] = fmt.Sprintf("%s %s", cursor, title)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, items...)
+}
+
+// RenderPromote shows where the selected draft can be promoted to
+func RenderPromote(m Model) string {
+	if len(m.drafts) == 0 || m.selectedDraftIndex >= len(m.drafts) {
+		return ""
+	}
+
+	draft := m.drafts[m.selectedDraftIndex]
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(highlight).
+		MarginBottom(1)
+
+	keyStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(highlight).
+		Width(6)
+
+	disabledStyle := lipgloss.NewStyle().
+		Foreground(subtle)
+
+	parts := []string{
+		titleStyle.Render("Promote Draft"),
+		wrapText(draft.Content, 50),
+		"",
+	}
+
+	option := func(key, label string, enabled bool) string {
+		if !enabled {
+			return disabledStyle.Render(key + "     " + label)
+		}
+		return keyStyle.Render(key) + label
+	}
+
+	projectName, taskTitle := "no project selected", "no task selected"
+	hasProject := len(m.projects) > 0 && m.selectedProjectIndex < len(m.projects)
+	hasTask := len(m.tasks) > 0 && m.selectedTaskIndex < len(m.tasks)
+	if hasProject {
+		projectName = m.projects[m.selectedProjectIndex].Name
+	}
+	if hasTask {
+		taskTitle = m.tasks[m.selectedTaskIndex].Title
+	}
+
+	parts = append(parts,
+		option("t", "Task in "+projectName, hasProject),
+		option("s", "Subtask of "+taskTitle, hasTask),
+		option("n", "Note on project "+projectName, hasProject),
+		option("N", "Note on task "+taskTitle, hasTask),
+	)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(subtle).
+		MarginTop(1)
+	parts = append(parts, helpStyle.Render("Select the target project/task first • Esc: Cancel"))
+	if errorLine := renderInlineError(m, 50); errorLine != "" {
+		parts = append(parts, errorLine)
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(highlight).
+		Padding(2, 4).
+		Width(60).
+		Render(lipgloss.JoinVertical(lipgloss.Left, parts...))
+
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		box,
+	)
+}
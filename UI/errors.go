@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// errorTimeout is how long an error stays in the status bar
+	errorTimeout = 5 * time.Second
+
+	// maxErrorLog caps how many errors the error log keeps
+	maxErrorLog = 100
+)
+
+var errorColor = lipgloss.AdaptiveColor{Light: "#D70000", Dark: "#FF5F5F"}
+
+// errMsg reports a failed command. Repository errors already say what
+// failed, so they are shown as they are.
+type errMsg struct {
+	err error
+}
+
+func (e errMsg) Error() string {
+	return e.err.Error()
+}
+
+// clearErrorMsg dismisses the status bar error if it is still the one
+// with the given id
+type clearErrorMsg struct {
+	id int
+}
+
+// errorEntry is one line of the error log
+type errorEntry struct {
+	at      time.Time
+	message string
+}
+
+// fail wraps err as an errMsg
+func fail(err error) tea.Msg {
+	return errMsg{err: err}
+}
+
+// invalid reports a validation problem with the submitted form
+func invalid(format string, args ...any) tea.Msg {
+	return errMsg{err: fmt.Errorf(format, args...)}
+}
+
+// showError logs the error, shows it in the status bar and schedules its
+// dismissal
+func (m *Model) showError(msg errMsg) tea.Cmd {
+	m.errorLog = append(m.errorLog, errorEntry{at: time.Now(), message: msg.Error()})
+	if len(m.errorLog) > maxErrorLog {
+		m.errorLog = m.errorLog[len(m.errorLog)-maxErrorLog:]
+	}
+
+	m.errorID++
+	m.statusError = msg.Error()
+
+	id := m.errorID
+	return tea.Tick(errorTimeout, func(time.Time) tea.Msg {
+		return clearErrorMsg{id: id}
+	})
+}
+
+// renderInlineError renders the current error for overlays that hide the
+// status bar, or "" when there is none
+func renderInlineError(m Model, width int) string {
+	if m.statusError == "" {
+		return ""
+	}
+
+	return lipgloss.NewStyle().
+		Foreground(errorColor).
+		MarginTop(1).
+		Render(wrapText(m.statusError, width))
+}
+
+// RenderErrorLog lists the errors of this session, newest first
+func RenderErrorLog(m Model) string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(highlight).
+		MarginBottom(1)
+
+	timeStyle := lipgloss.NewStyle().
+		Foreground(subtle).
+		Width(10)
+
+	messageStyle := lipgloss.NewStyle().
+		Foreground(errorColor)
+
+	parts := []string{titleStyle.Render(fmt.Sprintf("Error Log (%d)", len(m.errorLog)))}
+
+	if len(m.errorLog) == 0 {
+		parts = append(parts, lipgloss.NewStyle().Foreground(subtle).Render("No errors so far"))
+	}
+
+	// Leave room for the border and padding around the log
+	limit := max(m.height-12, 1)
+	for i := len(m.errorLog) - 1; i >= 0 && len(m.errorLog)-i <= limit; i-- {
+		entry := m.errorLog[i]
+		parts = append(parts, lipgloss.JoinHorizontal(lipgloss.Top,
+			timeStyle.Render(entry.at.Format("15:04:05")),
+			messageStyle.Render(wrapText(entry.message, 60)),
+		))
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(subtle).
+		MarginTop(1)
+	parts = append(parts, helpStyle.Render("c: Clear • Esc/L: Close"))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(highlight).
+		Padding(1, 2).
+		Width(80).
+		Render(lipgloss.JoinVertical(lipgloss.Left, parts...))
+
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		box,
+	)
+}
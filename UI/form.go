@@ -16,19 +16,25 @@ func RenderForm(m Model) string {
 
 	if m.mode == ModeCreateProject {
 		title = "Create New Project"
-		fields = []string{"Name:", "Description:"}
+		fields = []string{"Name:", "Description:", "Due Date:"}
 	} else if m.mode == ModeCreateTask {
 		title = "Create New Task"
-		fields = []string{"Title:", "Description:", "Priority:"}
+		fields = []string{"Title:", "Description:", "Priority:", "Due:", "Start:", "Scheduled:", "Repeat:"}
 	} else if m.mode == ModeEditProject {
 		title = "Edit Project"
-		fields = []string{"Name:", "Description:"}
+		fields = []string{"Name:", "Description:", "Due Date:"}
 	} else if m.mode == ModeEditTask {
 		title = "Edit Task"
-		fields = []string{"Title:", "Description:", "Priority:"}
+		fields = []string{"Title:", "Description:", "Priority:", "Due:", "Start:", "Scheduled:", "Repeat:"}
 	} else if m.mode == ModeCreateNote {
 		title = "Create New Note"
 		fields = []string{"Content:"}
+	} else if m.mode == ModeCreateDraft {
+		title = "Capture Draft"
+		fields = []string{"Content:"}
+	} else if m.mode == ModeEditDraft {
+		title = "Edit Draft"
+		fields = []string{"Content:"}
 	}
 
 	// Build form
@@ -60,6 +66,11 @@ func RenderForm(m Model) string {
 	helpText := "Tab/Shift+Tab: Switch fields • Enter: Submit • Esc: Cancel"
 	formParts = append(formParts, helpStyle.Render(helpText))
 
+	// The status bar is hidden behind the form, so show errors here
+	if errorLine := renderInlineError(m, 50); errorLine != "" {
+		formParts = append(formParts, errorLine)
+	}
+
 	// Container
 	formContent := lipgloss.JoinVertical(lipgloss.Left, formParts...)
 
@@ -36,18 +36,39 @@ func RenderHelp(m Model) string {
 		sectionTitleStyle.Render("Projects Section"),
 		keyStyle.Render("n") + descStyle.Render("Create new project"),
 		keyStyle.Render("e") + descStyle.Render("Edit selected project"),
-		keyStyle.Render("d") + descStyle.Render("Delete selected project"),
+		keyStyle.Render("d") + descStyle.Render("Delete selected project (asks first)"),
+		keyStyle.Render("a") + descStyle.Render("Archive or unarchive selected project"),
+		keyStyle.Render("f") + descStyle.Render("Show active, archived or all projects"),
+		keyStyle.Render("o") + descStyle.Render("Sort by due date or newest first"),
 		"",
 		sectionTitleStyle.Render("Tasks Section"),
 		keyStyle.Render("n") + descStyle.Render("Create new task"),
 		keyStyle.Render("s") + descStyle.Render("Create subtask (child of selected task)"),
 		keyStyle.Render("e") + descStyle.Render("Edit selected task"),
-		keyStyle.Render("d") + descStyle.Render("Delete selected task"),
-		keyStyle.Render("Space/Enter") + descStyle.Render("Toggle task completion"),
+		keyStyle.Render("d") + descStyle.Render("Delete selected task (asks first)"),
+		keyStyle.Render("Space/Enter") + descStyle.Render("Toggle task completion (recurring tasks repeat)"),
+		keyStyle.Render("] / [") + descStyle.Render("Move task to the next/previous status of the workflow"),
+		keyStyle.Render("b") + descStyle.Render("Mark blocker, then b on another task to link or unlink"),
+		keyStyle.Render("> / <") + descStyle.Render("Indent under the task above, or outdent"),
+		keyStyle.Render("m") + descStyle.Render("Move task with its subtasks to another project"),
+		keyStyle.Render("K/J") + descStyle.Render("Move task up/down among its siblings"),
+		keyStyle.Render("o") + descStyle.Render("Order the project's tasks by hand or by priority"),
+		"",
+		sectionTitleStyle.Render("Board"),
+		keyStyle.Render("v") + descStyle.Render("Switch between the grid and the project's board"),
+		keyStyle.Render("←/→ and ↑/↓") + descStyle.Render("Select a card in the next column or the same one"),
+		keyStyle.Render("h/l") + descStyle.Render("Move card to the previous/next column"),
+		keyStyle.Render("k/j") + descStyle.Render("Move card up/down its column, in projects ordered by hand"),
 		"",
 		sectionTitleStyle.Render("Notes Section"),
 		keyStyle.Render("n") + descStyle.Render("Create new note for selected task"),
 		"",
+		sectionTitleStyle.Render("Drafts Section"),
+		keyStyle.Render("n") + descStyle.Render("Capture new draft"),
+		keyStyle.Render("e") + descStyle.Render("Edit selected draft"),
+		keyStyle.Render("d") + descStyle.Render("Delete selected draft"),
+		keyStyle.Render("p") + descStyle.Render("Promote to task, subtask or note of the selection"),
+		"",
 		sectionTitleStyle.Render("Forms"),
 		keyStyle.Render("Tab/Shift+Tab") + descStyle.Render("Switch between form fields"),
 		keyStyle.Render("Enter") + descStyle.Render("Submit form"),
@@ -55,6 +76,9 @@ func RenderHelp(m Model) string {
 		"",
 		sectionTitleStyle.Render("General"),
 		keyStyle.Render("?") + descStyle.Render("Show this help screen"),
+		keyStyle.Render("u") + descStyle.Render("Undo last project or task deletion"),
+		keyStyle.Render("T") + descStyle.Render("Show trash (r: restore, D: delete permanently)"),
+		keyStyle.Render("L") + descStyle.Render("Show error log"),
 		keyStyle.Render("q or Ctrl+C") + descStyle.Render("Quit application"),
 		"",
 		"",
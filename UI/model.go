@@ -1,11 +1,16 @@
 package ui
 
 import (
+	"database/sql"
 	"fmt"
 	"palco/internal/database"
 	"palco/internal/database/models"
+	"palco/internal/dates"
+	"palco/internal/recurrence"
 	"palco/internal/repository"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -18,6 +23,13 @@ const (
 	columnWidth = 30
 )
 
+// Project list filters
+const (
+	ProjectFilterActive = iota
+	ProjectFilterArchived
+	ProjectFilterAll
+)
+
 // View modes
 const (
 	ModeNormal = iota
@@ -27,6 +39,13 @@ const (
 	ModeEditTask
 	ModeCreateNote
 	ModeHelp
+	ModeCreateDraft
+	ModeEditDraft
+	ModePromoteDraft
+	ModeErrorLog
+	ModeConfirmDelete
+	ModeTrash
+	ModeMoveTask
 )
 
 // Messages
@@ -35,12 +54,15 @@ type projectsLoadedMsg struct {
 }
 
 type tasksLoadedMsg struct {
-	tasks  []models.Task
-	depths []int
+	tasks    []models.Task
+	depths   []int
+	workflow models.Workflow
+	err      error // Loading failed; the lists are cleared so nothing stale stays actionable
 }
 
 type notesLoadedMsg struct {
 	notes []models.Note
+	err   error // Loading failed; the list is cleared
 }
 
 type projectCreatedMsg struct {
@@ -52,17 +74,48 @@ type taskCreatedMsg struct {
 }
 
 type taskUpdatedMsg struct {
-	task *models.Task
+	task      *models.Task
+	unblocked []models.Task // Tasks the completion freed up
 }
 
-type projectDeletedMsg struct{}
+type projectArchivedMsg struct{}
 
-type taskDeletedMsg struct{}
+type projectDeletedMsg struct {
+	deleted deletePreview
+}
+
+type taskDeletedMsg struct {
+	deleted deletePreview
+}
+
+type deletePreviewMsg struct {
+	preview deletePreview
+}
+
+type restoredMsg struct {
+	project bool
+}
+
+type clearUndoMsg struct {
+	id int
+}
 
 type noteCreatedMsg struct {
 	note *models.Note
 }
 
+type draftsLoadedMsg struct {
+	drafts []models.Draft
+}
+
+type draftSavedMsg struct {
+	draft *models.Draft
+}
+
+type draftDeletedMsg struct{}
+
+type draftPromotedMsg struct{}
+
 var (
 	// General.
 
@@ -111,9 +164,14 @@ type Model struct {
 	Db *database.DB
 
 	// Repositories
-	ProjectRepo *repository.ProjectRepository
-	TaskRepo    *repository.TaskRepository
-	NoteRepo    *repository.NoteRepository
+	ProjectRepo    *repository.ProjectRepository
+	TaskRepo       *repository.TaskRepository
+	NoteRepo       *repository.NoteRepository
+	DraftRepo      *repository.DraftRepository
+	WorkspaceRepo  *repository.WorkspaceRepository
+	TrashRepo      *repository.TrashRepository
+	DependencyRepo *repository.DependencyRepository
+	StatusRepo     *repository.StatusRepository
 
 	// Terminal dimensions
 	width  int
@@ -122,28 +180,62 @@ type Model struct {
 	// State
 	projects             []models.Project
 	tasks                []models.Task
-	taskDepths           []int // Depth level for each task (for indentation)
+	taskDepths           []int           // Depth level for each task (for indentation)
+	workflow             models.Workflow // Statuses of the selected project
 	notes                []models.Note
+	drafts               []models.Draft
+	blockedTasks         map[int64]bool // Tasks of the project waiting on open tasks
+	blockers             []models.Task  // Tasks the selected task waits on
+	dependents           []models.Task  // Tasks waiting on the selected task
+	blocker              *models.Task   // Marked with b, to link to another task
 	selectedProjectIndex int
 	selectedTaskIndex    int
-	activeSection        int // 0: projects, 1: tasks, 2: notes, 3: details, 4: drafts
-	noteContext          int // 0: project notes, 1: task notes
+	selectedDraftIndex   int
+	selectTaskID         int64 // Task to select once the task list reloads
+	board                bool  // Show the selected project as a board instead of the grid
+	activeSection        int   // 0: projects, 1: tasks, 2: notes, 3: details, 4: drafts
+	projectFilter        int   // ProjectFilterActive, ProjectFilterArchived or ProjectFilterAll
+	sortProjectsByDue    bool  // Order projects by due date instead of newest first
+	noteContext          int   // 0: project notes, 1: task notes
 
 	// Form state
 	mode         int
 	formInputs   []textinput.Model
 	focusedInput int
 	parentTaskID *int64 // Used when creating a subtask
+
+	// Error state
+	statusError string // Shown in the status bar until dismissed
+	errorID     int    // Identifies statusError for its timed dismissal
+	errorLog    []errorEntry
+
+	// Notice state
+	statusNotice string // Shown in the status bar until dismissed
+	noticeID     int    // Identifies statusNotice for its timed dismissal
+
+	// Delete state
+	pendingDelete *deletePreview // Awaiting confirmation
+	undo          *undoEntry     // Last deletion, until the undo window closes
+	undoID        int
+
+	// Trash state
+	trash              []models.TrashItem
+	selectedTrashIndex int
+	confirmTrashDelete bool // Permanent delete of the selected item awaits y
+
+	// Move state
+	moveTargets       []models.Project // Projects the selected task can move to
+	selectedMoveIndex int
 }
 
 func (m Model) Init() tea.Cmd {
-	return m.loadProjects
+	return tea.Batch(m.loadProjects, m.loadDrafts)
 }
 
 // initProjectForm initializes the form for creating a new project
 func (m *Model) initProjectForm() {
 	m.mode = ModeCreateProject
-	m.formInputs = make([]textinput.Model, 2)
+	m.formInputs = make([]textinput.Model, 3)
 	m.focusedInput = 0
 
 	// Name input
@@ -158,6 +250,12 @@ func (m *Model) initProjectForm() {
 	m.formInputs[1].Placeholder = "Description (optional)"
 	m.formInputs[1].CharLimit = 500
 	m.formInputs[1].Width = 50
+
+	// Due date input
+	m.formInputs[2] = textinput.New()
+	m.formInputs[2].Placeholder = "Due date (optional, e.g. 2026-11-01, in 2 weeks, eom)"
+	m.formInputs[2].CharLimit = 40
+	m.formInputs[2].Width = 50
 }
 
 // initTaskForm initializes the form for creating a new task
@@ -168,7 +266,7 @@ func (m *Model) initTaskForm() {
 
 	m.mode = ModeCreateTask
 	m.parentTaskID = nil // Creating a top-level task
-	m.formInputs = make([]textinput.Model, 3)
+	m.formInputs = make([]textinput.Model, 7)
 	m.focusedInput = 0
 
 	// Title input
@@ -190,6 +288,12 @@ func (m *Model) initTaskForm() {
 	m.formInputs[2].SetValue("0")
 	m.formInputs[2].CharLimit = 1
 	m.formInputs[2].Width = 50
+
+	// Date inputs
+	m.formInputs[3] = newDateInput("Due date (optional, e.g. friday, tomorrow 3pm)", sql.NullTime{})
+	m.formInputs[4] = newDateInput("Start date (optional)", sql.NullTime{})
+	m.formInputs[5] = newDateInput("Scheduled for (optional)", sql.NullTime{})
+	m.formInputs[6] = newRepeatInput(sql.NullString{})
 }
 
 // initSubtaskForm initializes the form for creating a subtask
@@ -203,7 +307,7 @@ func (m *Model) initSubtaskForm() {
 	m.parentTaskID = &taskID
 
 	m.mode = ModeCreateTask
-	m.formInputs = make([]textinput.Model, 3)
+	m.formInputs = make([]textinput.Model, 7)
 	m.focusedInput = 0
 
 	// Title input
@@ -225,13 +329,19 @@ func (m *Model) initSubtaskForm() {
 	m.formInputs[2].SetValue("0")
 	m.formInputs[2].CharLimit = 1
 	m.formInputs[2].Width = 50
+
+	// Date inputs
+	m.formInputs[3] = newDateInput("Due date (optional, e.g. friday, tomorrow 3pm)", sql.NullTime{})
+	m.formInputs[4] = newDateInput("Start date (optional)", sql.NullTime{})
+	m.formInputs[5] = newDateInput("Scheduled for (optional)", sql.NullTime{})
+	m.formInputs[6] = newRepeatInput(sql.NullString{})
 }
 
 // createProject creates a new project from form inputs
 func (m Model) createProject() tea.Msg {
 	name := m.formInputs[0].Value()
 	if name == "" {
-		return nil
+		return invalid("project name is required")
 	}
 
 	var description *string
@@ -239,10 +349,14 @@ func (m Model) createProject() tea.Msg {
 		description = &desc
 	}
 
-	project, err := m.ProjectRepo.Create(name, description, nil)
+	dueDate, err := parseFormDate(m.formInputs[2].Value())
 	if err != nil {
-		// TODO: Handle error
-		return nil
+		return fail(err)
+	}
+
+	project, err := m.ProjectRepo.Create(name, description, dueDate)
+	if err != nil {
+		return fail(err)
 	}
 
 	return projectCreatedMsg{project: project}
@@ -252,11 +366,11 @@ func (m Model) createProject() tea.Msg {
 func (m Model) createTask() tea.Msg {
 	title := m.formInputs[0].Value()
 	if title == "" {
-		return nil
+		return invalid("task title is required")
 	}
 
 	if len(m.projects) == 0 {
-		return nil
+		return invalid("no project selected")
 	}
 
 	projectID := m.projects[m.selectedProjectIndex].ID
@@ -266,19 +380,26 @@ func (m Model) createTask() tea.Msg {
 		description = &desc
 	}
 
-	// Parse priority from form input (default to 0 if invalid)
-	priority := 0
-	if priorityStr := m.formInputs[2].Value(); priorityStr != "" {
-		if p, err := strconv.Atoi(priorityStr); err == nil && p >= 0 && p <= 4 {
-			priority = p
-		}
+	// Parse priority from form input (default to 0 if empty)
+	priority, err := parseFormPriority(m.formInputs[2].Value(), models.PriorityNone)
+	if err != nil {
+		return fail(err)
+	}
+
+	dates, err := m.parseFormDates()
+	if err != nil {
+		return fail(err)
+	}
+
+	repeat, err := parseFormRepeat(m.formInputs[6].Value())
+	if err != nil {
+		return fail(err)
 	}
 
 	// Use parentTaskID if creating a subtask, otherwise nil for top-level task
-	task, err := m.TaskRepo.Create(projectID, m.parentTaskID, title, description, priority)
+	task, err := m.TaskRepo.Create(projectID, m.parentTaskID, title, description, priority, dates, repeat)
 	if err != nil {
-		// TODO: Handle error
-		return nil
+		return fail(err)
 	}
 
 	return taskCreatedMsg{task: task}
@@ -293,7 +414,7 @@ func (m *Model) initEditProjectForm() {
 	project := m.projects[m.selectedProjectIndex]
 
 	m.mode = ModeEditProject
-	m.formInputs = make([]textinput.Model, 2)
+	m.formInputs = make([]textinput.Model, 3)
 	m.focusedInput = 0
 
 	// Name input
@@ -312,6 +433,15 @@ func (m *Model) initEditProjectForm() {
 	}
 	m.formInputs[1].CharLimit = 500
 	m.formInputs[1].Width = 50
+
+	// Due date input
+	m.formInputs[2] = textinput.New()
+	m.formInputs[2].Placeholder = "Due date (optional, e.g. 2026-11-01, in 2 weeks, eom)"
+	if project.DueDate.Valid {
+		m.formInputs[2].SetValue(project.DueDate.Time.Format(dates.DateLayout))
+	}
+	m.formInputs[2].CharLimit = 40
+	m.formInputs[2].Width = 50
 }
 
 // initNoteForm initializes the form for creating a new note
@@ -366,7 +496,7 @@ func (m *Model) initEditTaskForm() {
 	task := m.tasks[m.selectedTaskIndex]
 
 	m.mode = ModeEditTask
-	m.formInputs = make([]textinput.Model, 3)
+	m.formInputs = make([]textinput.Model, 7)
 	m.focusedInput = 0
 
 	// Title input
@@ -398,6 +528,12 @@ func (m *Model) initEditTaskForm() {
 	m.formInputs[2].SetValue(fmt.Sprintf("%d", task.Priority))
 	m.formInputs[2].CharLimit = 1
 	m.formInputs[2].Width = 50
+
+	// Date inputs
+	m.formInputs[3] = newDateInput("Due date (optional, e.g. friday, tomorrow 3pm)", task.DueAt)
+	m.formInputs[4] = newDateInput("Start date (optional)", task.StartAt)
+	m.formInputs[5] = newDateInput("Scheduled for (optional)", task.ScheduledFor)
+	m.formInputs[6] = newRepeatInput(task.Recurrence)
 }
 
 // updateProject updates the selected project from form inputs
@@ -408,7 +544,7 @@ func (m Model) updateProject() tea.Msg {
 
 	name := m.formInputs[0].Value()
 	if name == "" {
-		return nil
+		return invalid("project name is required")
 	}
 
 	project := m.projects[m.selectedProjectIndex]
@@ -418,10 +554,14 @@ func (m Model) updateProject() tea.Msg {
 		description = &desc
 	}
 
-	updatedProject, err := m.ProjectRepo.Update(project.ID, name, description, nil)
+	dueDate, err := parseFormDate(m.formInputs[2].Value())
 	if err != nil {
-		// TODO: Handle error
-		return nil
+		return fail(err)
+	}
+
+	updatedProject, err := m.ProjectRepo.Update(project.ID, name, description, dueDate)
+	if err != nil {
+		return fail(err)
 	}
 
 	return projectCreatedMsg{project: updatedProject}
@@ -431,7 +571,7 @@ func (m Model) updateProject() tea.Msg {
 func (m Model) createNote() tea.Msg {
 	content := m.formInputs[0].Value()
 	if content == "" {
-		return nil
+		return invalid("note content is required")
 	}
 
 	var note *models.Note
@@ -440,22 +580,21 @@ func (m Model) createNote() tea.Msg {
 	if m.noteContext == 0 {
 		// Create project note
 		if len(m.projects) == 0 {
-			return nil
+			return invalid("no project selected")
 		}
 		projectID := m.projects[m.selectedProjectIndex].ID
 		note, err = m.NoteRepo.CreateForProject(projectID, content)
 	} else {
 		// Create task note
 		if len(m.tasks) == 0 {
-			return nil
+			return invalid("no task selected")
 		}
 		taskID := m.tasks[m.selectedTaskIndex].ID
 		note, err = m.NoteRepo.CreateForTask(taskID, content)
 	}
 
 	if err != nil {
-		// TODO: Handle error
-		return nil
+		return fail(err)
 	}
 
 	return noteCreatedMsg{note: note}
@@ -469,24 +608,31 @@ func (m Model) updateTask() tea.Msg {
 
 	title := m.formInputs[0].Value()
 	if title == "" {
-		return nil
+		return invalid("task title is required")
 	}
 
 	task := m.tasks[m.selectedTaskIndex]
 
-	// Parse priority from form input (default to current priority if invalid)
-	priority := task.Priority
-	if priorityStr := m.formInputs[2].Value(); priorityStr != "" {
-		if p, err := strconv.Atoi(priorityStr); err == nil && p >= 0 && p <= 4 {
-			priority = p
-		}
+	// Parse priority from form input (default to current priority if empty)
+	priority, err := parseFormPriority(m.formInputs[2].Value(), task.Priority)
+	if err != nil {
+		return fail(err)
+	}
+
+	dates, err := m.parseFormDates()
+	if err != nil {
+		return fail(err)
+	}
+
+	repeat, err := parseFormRepeat(m.formInputs[6].Value())
+	if err != nil {
+		return fail(err)
 	}
 
 	// Update task
-	updatedTask, err := m.TaskRepo.Update(task.ID, title, priority, task.Completed)
+	updatedTask, err := m.TaskRepo.Update(task.ID, title, priority, dates, repeat)
 	if err != nil {
-		// TODO: Handle error
-		return nil
+		return fail(err)
 	}
 
 	// Update description note if provided
@@ -509,138 +655,335 @@ func (m Model) updateTask() tea.Msg {
 		}
 
 		if err != nil {
-			// TODO: Handle error
+			return fail(err)
 		}
 	}
 
 	return taskUpdatedMsg{task: updatedTask}
 }
 
-// toggleTaskCompletion toggles the completion status of the selected task
+// toggleTaskCompletion toggles the completion status of the selected task.
+// Completing a recurring task creates its next occurrence, and completing
+// a blocker reports the tasks it unblocks.
 func (m Model) toggleTaskCompletion() tea.Msg {
 	if len(m.tasks) == 0 || m.selectedTaskIndex >= len(m.tasks) {
 		return nil
 	}
 
 	task := m.tasks[m.selectedTaskIndex]
+	completion, err := m.TaskRepo.SetCompleted(task.ID, !task.Completed, time.Now())
+	if err != nil {
+		return fail(err)
+	}
 
-	// Toggle completion
-	newCompleted := !task.Completed
+	return taskUpdatedMsg{task: completion.Task, unblocked: completion.Unblocked}
+}
 
-	updatedTask, err := m.TaskRepo.Update(task.ID, task.Title, task.Priority, newCompleted)
-	if err != nil {
-		// TODO: Handle error
+// toggleProjectArchived archives the selected project, or unarchives it
+// when it is archived already
+func (m Model) toggleProjectArchived() tea.Msg {
+	if len(m.projects) == 0 || m.selectedProjectIndex >= len(m.projects) {
 		return nil
 	}
 
-	return taskUpdatedMsg{task: updatedTask}
+	project := m.projects[m.selectedProjectIndex]
+
+	var err error
+	if project.Archived {
+		err = m.ProjectRepo.Unarchive(project.ID)
+	} else {
+		err = m.ProjectRepo.Archive(project.ID)
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	return projectArchivedMsg{}
 }
 
-// deleteProject deletes the currently selected project
-func (m Model) deleteProject() tea.Msg {
+// archivedProjectError reports that tasks cannot be added to the selected
+// project because it is archived, or returns nil when it is not
+func (m Model) archivedProjectError() error {
 	if len(m.projects) == 0 || m.selectedProjectIndex >= len(m.projects) {
 		return nil
 	}
 
 	project := m.projects[m.selectedProjectIndex]
-	err := m.ProjectRepo.Delete(project.ID)
-	if err != nil {
-		// TODO: Handle error
+	if !project.Archived {
 		return nil
 	}
+	return fmt.Errorf("project %q is archived, unarchive it with a to add tasks", project.Name)
+}
+
+// previewDeleteProject counts what deleting the selected project removes,
+// so it can be confirmed
+func (m Model) previewDeleteProject() tea.Msg {
+	if len(m.projects) == 0 || m.selectedProjectIndex >= len(m.projects) {
+		return nil
+	}
+
+	snapshot, err := m.WorkspaceRepo.ProjectSubtree(m.projects[m.selectedProjectIndex].ID)
+	if err != nil {
+		return fail(err)
+	}
 
-	return projectDeletedMsg{}
+	return deletePreviewMsg{preview: newDeletePreview(snapshot)}
 }
 
-// deleteTask deletes the currently selected task
-func (m Model) deleteTask() tea.Msg {
+// previewDeleteTask counts what deleting the selected task removes, so it
+// can be confirmed
+func (m Model) previewDeleteTask() tea.Msg {
 	if len(m.tasks) == 0 || m.selectedTaskIndex >= len(m.tasks) {
 		return nil
 	}
 
-	task := m.tasks[m.selectedTaskIndex]
-	err := m.TaskRepo.Delete(task.ID)
+	snapshot, err := m.WorkspaceRepo.TaskSubtree(m.tasks[m.selectedTaskIndex].ID)
 	if err != nil {
-		// TODO: Handle error
+		return fail(err)
+	}
+
+	return deletePreviewMsg{preview: newDeletePreview(snapshot)}
+}
+
+// confirmDelete moves the item awaiting confirmation to the trash
+func (m Model) confirmDelete() tea.Msg {
+	if m.pendingDelete == nil {
 		return nil
 	}
+	deleted := *m.pendingDelete
+
+	if deleted.project {
+		if err := m.ProjectRepo.Delete(deleted.id); err != nil {
+			return fail(err)
+		}
+		return projectDeletedMsg{deleted: deleted}
+	}
 
-	return taskDeletedMsg{}
+	if err := m.TaskRepo.Delete(deleted.id); err != nil {
+		return fail(err)
+	}
+	return taskDeletedMsg{deleted: deleted}
 }
 
-// loadProjects loads all active projects from the database
-func (m Model) loadProjects() tea.Msg {
-	projects, err := m.ProjectRepo.GetAllActive()
+// undoDelete takes the last deletion back out of the trash
+func (m Model) undoDelete() tea.Msg {
+	if m.undo == nil {
+		return nil
+	}
+
+	if err := m.TrashRepo.Restore(m.undo.deleted.trashKind(), m.undo.deleted.id); err != nil {
+		return fail(err)
+	}
+
+	return restoredMsg{project: m.undo.deleted.project}
+}
+
+// rememberDeletion offers undo for deleted and schedules the end of the
+// undo window
+func (m *Model) rememberDeletion(deleted deletePreview) tea.Cmd {
+	m.undoID++
+	m.undo = &undoEntry{id: m.undoID, deleted: deleted}
+
+	id := m.undoID
+	return tea.Tick(undoWindow, func(time.Time) tea.Msg {
+		return clearUndoMsg{id: id}
+	})
+}
+
+// initDraftForm initializes the form for capturing a new draft
+func (m *Model) initDraftForm() {
+	m.mode = ModeCreateDraft
+	m.formInputs = make([]textinput.Model, 1)
+	m.focusedInput = 0
+
+	// Content input
+	m.formInputs[0] = textinput.New()
+	m.formInputs[0].Placeholder = "What's on your mind?"
+	m.formInputs[0].Focus()
+	m.formInputs[0].CharLimit = 1000
+	m.formInputs[0].Width = 50
+}
+
+// initEditDraftForm initializes the form for editing the selected draft
+func (m *Model) initEditDraftForm() {
+	if len(m.drafts) == 0 || m.selectedDraftIndex >= len(m.drafts) {
+		return
+	}
+
+	draft := m.drafts[m.selectedDraftIndex]
+
+	m.mode = ModeEditDraft
+	m.formInputs = make([]textinput.Model, 1)
+	m.focusedInput = 0
+
+	// Content input
+	m.formInputs[0] = textinput.New()
+	m.formInputs[0].Placeholder = "What's on your mind?"
+	m.formInputs[0].SetValue(draft.Content)
+	m.formInputs[0].Focus()
+	m.formInputs[0].CharLimit = 1000
+	m.formInputs[0].Width = 50
+}
+
+// createDraft creates a new draft from form inputs
+func (m Model) createDraft() tea.Msg {
+	content := m.formInputs[0].Value()
+	if content == "" {
+		return invalid("draft content is required")
+	}
+
+	draft, err := m.DraftRepo.Create(content)
 	if err != nil {
-		// For now, return empty slice on error
-		// TODO: Add error handling
-		return projectsLoadedMsg{projects: []models.Project{}}
+		return fail(err)
 	}
-	return projectsLoadedMsg{projects: projects}
+
+	return draftSavedMsg{draft: draft}
 }
 
-// loadTasks loads tasks for the currently selected project
-func (m Model) loadTasks() tea.Msg {
-	if len(m.projects) == 0 || m.selectedProjectIndex >= len(m.projects) {
-		return tasksLoadedMsg{tasks: []models.Task{}}
+// updateDraft updates the selected draft from form inputs
+func (m Model) updateDraft() tea.Msg {
+	if len(m.drafts) == 0 || m.selectedDraftIndex >= len(m.drafts) {
+		return nil
 	}
 
-	projectID := m.projects[m.selectedProjectIndex].ID
-	tasks, err := m.TaskRepo.GetByProjectID(projectID)
+	content := m.formInputs[0].Value()
+	if content == "" {
+		return invalid("draft content is required")
+	}
+
+	draft, err := m.DraftRepo.Update(m.drafts[m.selectedDraftIndex].ID, content)
 	if err != nil {
-		// For now, return empty slice on error
-		// TODO: Add error handling
-		return tasksLoadedMsg{tasks: []models.Task{}}
+		return fail(err)
 	}
 
-	// Organize tasks hierarchically (parents followed by their children, recursively)
-	hierarchicalTasks, depths := organizeTasksHierarchically(tasks)
+	return draftSavedMsg{draft: draft}
+}
+
+// deleteDraft deletes the currently selected draft
+func (m Model) deleteDraft() tea.Msg {
+	if len(m.drafts) == 0 || m.selectedDraftIndex >= len(m.drafts) {
+		return nil
+	}
 
-	return tasksLoadedMsg{tasks: hierarchicalTasks, depths: depths}
+	err := m.DraftRepo.Delete(m.drafts[m.selectedDraftIndex].ID)
+	if err != nil {
+		return fail(err)
+	}
+
+	return draftDeletedMsg{}
 }
 
-// organizeTasksHierarchically reorganizes tasks so subtasks appear under their parents (recursively)
-func organizeTasksHierarchically(tasks []models.Task) ([]models.Task, []int) {
-	if len(tasks) == 0 {
-		return tasks, []int{}
+// promoteDraft files the selected draft as a task, subtask or note of the
+// selected project or task. target is the key pressed in the promote dialog.
+func (m Model) promoteDraft(target string) tea.Cmd {
+	if len(m.drafts) == 0 || m.selectedDraftIndex >= len(m.drafts) {
+		return nil
 	}
 
-	// Build a map of parent ID to children
-	subtasksByParent := make(map[int64][]models.Task)
-	var rootTasks []models.Task
+	draftID := m.drafts[m.selectedDraftIndex].ID
+	hasProject := len(m.projects) > 0 && m.selectedProjectIndex < len(m.projects)
+	hasTask := len(m.tasks) > 0 && m.selectedTaskIndex < len(m.tasks)
 
-	for _, task := range tasks {
-		if task.ParentTaskID.Valid {
-			parentID := task.ParentTaskID.Int64
-			subtasksByParent[parentID] = append(subtasksByParent[parentID], task)
-		} else {
-			rootTasks = append(rootTasks, task)
-		}
+	var projectID, taskID int64
+	if hasProject {
+		projectID = m.projects[m.selectedProjectIndex].ID
+	}
+	if hasTask {
+		taskID = m.tasks[m.selectedTaskIndex].ID
 	}
 
-	// Recursively build the hierarchical list
-	var result []models.Task
-	var depths []int
+	// Tasks cannot be added to archived projects
+	if target == "t" || target == "s" {
+		if err := m.archivedProjectError(); err != nil {
+			return func() tea.Msg { return fail(err) }
+		}
+	}
 
-	var addTaskAndChildren func(task models.Task, depth int)
-	addTaskAndChildren = func(task models.Task, depth int) {
-		result = append(result, task)
-		depths = append(depths, depth)
+	var promote func() error
+	switch {
+	case target == "t" && hasProject:
+		promote = func() error {
+			_, err := m.DraftRepo.PromoteToTask(draftID, projectID, nil, models.PriorityNone)
+			return err
+		}
+	case target == "s" && hasTask:
+		promote = func() error {
+			_, err := m.DraftRepo.PromoteToTask(draftID, projectID, &taskID, models.PriorityNone)
+			return err
+		}
+	case target == "n" && hasProject:
+		promote = func() error {
+			_, err := m.DraftRepo.PromoteToNote(draftID, &projectID, nil)
+			return err
+		}
+	case target == "N" && hasTask:
+		promote = func() error {
+			_, err := m.DraftRepo.PromoteToNote(draftID, nil, &taskID)
+			return err
+		}
+	default:
+		return nil
+	}
 
-		// Add children recursively
-		if children, exists := subtasksByParent[task.ID]; exists {
-			for _, child := range children {
-				addTaskAndChildren(child, depth+1)
-			}
+	return func() tea.Msg {
+		if err := promote(); err != nil {
+			return fail(err)
 		}
+		return draftPromotedMsg{}
 	}
+}
 
-	// Add all root tasks and their descendants
-	for _, rootTask := range rootTasks {
-		addTaskAndChildren(rootTask, 0)
+// loadDrafts loads all drafts from the database
+func (m Model) loadDrafts() tea.Msg {
+	drafts, err := m.DraftRepo.GetAll()
+	if err != nil {
+		return fail(err)
 	}
+	return draftsLoadedMsg{drafts: drafts}
+}
 
-	return result, depths
+// loadProjects loads the projects matching the project filter
+func (m Model) loadProjects() tea.Msg {
+	var projects []models.Project
+	var err error
+	switch m.projectFilter {
+	case ProjectFilterArchived:
+		projects, err = m.ProjectRepo.GetAllArchived()
+	case ProjectFilterAll:
+		projects, err = m.ProjectRepo.GetAll()
+	default:
+		projects, err = m.ProjectRepo.GetAllActive()
+	}
+	if err != nil {
+		return fail(err)
+	}
+	if m.sortProjectsByDue {
+		models.SortByDueDate(projects)
+	}
+	return projectsLoadedMsg{projects: projects}
+}
+
+// loadTasks loads tasks for the currently selected project
+func (m Model) loadTasks() tea.Msg {
+	if len(m.projects) == 0 || m.selectedProjectIndex >= len(m.projects) {
+		return tasksLoadedMsg{tasks: []models.Task{}}
+	}
+
+	projectID := m.projects[m.selectedProjectIndex].ID
+	tasks, err := m.TaskRepo.GetByProjectID(projectID)
+	if err != nil {
+		return tasksLoadedMsg{tasks: []models.Task{}, err: err}
+	}
+
+	workflow, err := m.StatusRepo.GetWorkflow(projectID)
+	if err != nil {
+		return tasksLoadedMsg{tasks: []models.Task{}, err: err}
+	}
+
+	// Organize tasks hierarchically (parents followed by their children, recursively)
+	hierarchicalTasks, depths := models.OrganizeTasksHierarchically(tasks)
+
+	return tasksLoadedMsg{tasks: hierarchicalTasks, depths: depths, workflow: workflow}
 }
 
 // loadNotes loads notes for the currently selected task
@@ -652,9 +995,7 @@ func (m Model) loadNotes() tea.Msg {
 	taskID := m.tasks[m.selectedTaskIndex].ID
 	notes, err := m.NoteRepo.GetByTaskID(taskID)
 	if err != nil {
-		// For now, return empty slice on error
-		// TODO: Add error handling
-		return notesLoadedMsg{notes: []models.Note{}}
+		return notesLoadedMsg{notes: []models.Note{}, err: err}
 	}
 	m.noteContext = 1 // Task notes context
 	return notesLoadedMsg{notes: notes}
@@ -669,9 +1010,7 @@ func (m Model) loadProjectNotes() tea.Msg {
 	projectID := m.projects[m.selectedProjectIndex].ID
 	notes, err := m.NoteRepo.GetByProjectID(projectID)
 	if err != nil {
-		// For now, return empty slice on error
-		// TODO: Add error handling
-		return notesLoadedMsg{notes: []models.Note{}}
+		return notesLoadedMsg{notes: []models.Note{}, err: err}
 	}
 	m.noteContext = 0 // Project notes context
 	return notesLoadedMsg{notes: notes}
@@ -687,24 +1026,71 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.selectedProjectIndex = 0
 			return m, tea.Batch(m.loadTasks, m.loadProjectNotes)
 		}
+
+		// Nothing is selected, so the last project's lists must not stay actionable
+		m.selectedProjectIndex = 0
+		m.tasks, m.taskDepths, m.workflow = []models.Task{}, nil, nil
+		m.selectedTaskIndex = 0
+		m.notes = []models.Note{}
+		m.blockers, m.dependents, m.blockedTasks = nil, nil, nil
 		return m, nil
 
 	// Handle tasks loaded
 	case tasksLoadedMsg:
 		m.tasks = msg.tasks
 		m.taskDepths = msg.depths
+		m.workflow = msg.workflow
 		m.selectedTaskIndex = 0
+		for i, task := range m.tasks {
+			if task.ID == m.selectTaskID {
+				m.selectedTaskIndex = i
+			}
+		}
+		m.selectTaskID = 0
+
+		// A task indented or created as a subtask on the board is not a card
+		if m.board {
+			m.selectedTaskIndex = cardOf(m, m.selectedTaskIndex)
+		}
 		if len(m.tasks) > 0 {
-			return m, m.loadNotes
+			return m, tea.Batch(m.loadNotes, m.loadDependencies, m.loadBlockedTasks)
 		}
 		m.notes = []models.Note{}
+		m.blockers, m.dependents, m.blockedTasks = nil, nil, nil
+		if msg.err != nil {
+			return m, m.showError(errMsg{err: msg.err})
+		}
 		return m, nil
 
 	// Handle notes loaded
 	case notesLoadedMsg:
 		m.notes = msg.notes
+		if msg.err != nil {
+			return m, m.showError(errMsg{err: msg.err})
+		}
+		return m, nil
+
+	// Handle dependencies of the selected task loaded
+	case dependenciesLoadedMsg:
+		m.blockers = msg.blockers
+		m.dependents = msg.dependents
 		return m, nil
 
+	// Handle blocked tasks of the project loaded
+	case blockedTasksLoadedMsg:
+		m.blockedTasks = msg.blocked
+		return m, nil
+
+	// Handle tasks linked or unlinked
+	case dependencyChangedMsg:
+		verb := "now waits on"
+		if !msg.linked {
+			verb = "no longer waits on"
+		}
+		notice := m.showNotice(fmt.Sprintf("%s %s %s", msg.task.Title, verb, msg.blocker.Title))
+		m.blocker = nil
+		return m, tea.Batch(notice, m.loadDependencies, m.loadBlockedTasks)
+
 	// Handle project created
 	case projectCreatedMsg:
 		m.mode = ModeNormal
@@ -721,17 +1107,96 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case taskUpdatedMsg:
 		m.mode = ModeNormal
 		m.formInputs = nil
+		if len(msg.unblocked) > 0 {
+			return m, tea.Batch(m.loadTasks, m.showNotice(unblockedNotice(msg.unblocked)))
+		}
 		return m, m.loadTasks
 
+	// Handle task status changed, keeping the task selected
+	case taskStatusChangedMsg:
+		m.selectTaskID = msg.task.ID
+		return m, tea.Batch(m.loadTasks, m.showNotice(statusNotice(msg)))
+
+	// Handle move dialog opened
+	case moveTargetsLoadedMsg:
+		m.moveTargets = msg.projects
+		m.selectedMoveIndex = 0
+		m.mode = ModeMoveTask
+		return m, nil
+
+	// Handle task moved, keeping it selected when it stays in the project
+	case taskMovedMsg:
+		m.mode = ModeNormal
+		m.selectTaskID = msg.task.ID
+		return m, m.loadTasks
+
+	// Handle task order switched, keeping the selected task selected
+	case taskOrderChangedMsg:
+		for i, project := range m.projects {
+			if project.ID == msg.project.ID {
+				m.projects[i] = msg.project
+			}
+		}
+		if len(m.tasks) > 0 {
+			m.selectTaskID = m.tasks[m.selectedTaskIndex].ID
+		}
+		return m, m.loadTasks
+
+	// Handle delete awaiting confirmation
+	case deletePreviewMsg:
+		m.pendingDelete = &msg.preview
+		m.mode = ModeConfirmDelete
+		return m, nil
+
+	// Handle project archived or unarchived
+	case projectArchivedMsg:
+		return m, m.loadProjects
+
 	// Handle project deleted
 	case projectDeletedMsg:
+		m.mode = ModeNormal
+		m.pendingDelete = nil
 		m.selectedProjectIndex = 0
-		return m, m.loadProjects
+		return m, tea.Batch(m.loadProjects, m.rememberDeletion(msg.deleted))
 
 	// Handle task deleted
 	case taskDeletedMsg:
+		m.mode = ModeNormal
+		m.pendingDelete = nil
 		m.selectedTaskIndex = 0
-		return m, m.loadTasks
+		return m, tea.Batch(m.loadTasks, m.rememberDeletion(msg.deleted))
+
+	// Handle items restored from the trash
+	case restoredMsg:
+		m.undo = nil
+		reload := []tea.Cmd{m.loadTasks, m.loadProjectNotes}
+		if msg.project {
+			reload = []tea.Cmd{m.loadProjects}
+		}
+		if m.mode == ModeTrash {
+			reload = append(reload, m.loadTrash)
+		}
+		return m, tea.Batch(reload...)
+
+	// Handle trash loaded
+	case trashLoadedMsg:
+		m.trash = msg.items
+		if m.selectedTrashIndex >= len(m.trash) {
+			m.selectedTrashIndex = max(len(m.trash)-1, 0)
+		}
+		return m, nil
+
+	// Handle trash item deleted for good
+	case trashDeletedMsg:
+		m.confirmTrashDelete = false
+		return m, m.loadTrash
+
+	// Handle the end of the undo window
+	case clearUndoMsg:
+		if m.undo != nil && m.undo.id == msg.id {
+			m.undo = nil
+		}
+		return m, nil
 
 	// Handle note created
 	case noteCreatedMsg:
@@ -742,6 +1207,47 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, m.loadNotes
 
+	// Handle drafts loaded
+	case draftsLoadedMsg:
+		m.drafts = msg.drafts
+		if m.selectedDraftIndex >= len(m.drafts) {
+			m.selectedDraftIndex = max(len(m.drafts)-1, 0)
+		}
+		return m, nil
+
+	// Handle draft created or edited
+	case draftSavedMsg:
+		m.mode = ModeNormal
+		m.formInputs = nil
+		return m, m.loadDrafts
+
+	// Handle draft deleted
+	case draftDeletedMsg:
+		return m, m.loadDrafts
+
+	// Handle draft promoted, the new task or note shows up in its panel
+	case draftPromotedMsg:
+		m.mode = ModeNormal
+		return m, tea.Batch(m.loadDrafts, m.loadTasks, m.loadProjectNotes)
+
+	// Handle failed commands
+	case errMsg:
+		return m, m.showError(msg)
+
+	// Handle timed error dismissal
+	case clearErrorMsg:
+		if msg.id == m.errorID {
+			m.statusError = ""
+		}
+		return m, nil
+
+	// Handle timed notice dismissal
+	case clearNoticeMsg:
+		if msg.id == m.noticeID {
+			m.statusNotice = ""
+		}
+		return m, nil
+
 	// Handle window resize
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -757,8 +1263,53 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// Handle error log
+		if m.mode == ModeErrorLog {
+			switch msg.String() {
+			case "c":
+				m.errorLog = nil
+			case "esc", "L", "q":
+				m.mode = ModeNormal
+			}
+			return m, nil
+		}
+
+		// Handle trash view
+		if m.mode == ModeTrash {
+			return m.updateTrash(msg)
+		}
+
+		// Handle move dialog
+		if m.mode == ModeMoveTask {
+			return m.updateMoveTask(msg)
+		}
+
+		// Handle delete confirmation
+		if m.mode == ModeConfirmDelete {
+			switch msg.String() {
+			case "y", "Y":
+				return m, m.confirmDelete
+			case "n", "N", "esc", "q":
+				m.mode = ModeNormal
+				m.pendingDelete = nil
+			}
+			return m, nil
+		}
+
+		// Handle promote dialog
+		if m.mode == ModePromoteDraft {
+			switch msg.String() {
+			case "esc":
+				m.mode = ModeNormal
+				return m, nil
+			case "t", "s", "n", "N":
+				return m, m.promoteDraft(msg.String())
+			}
+			return m, nil
+		}
+
 		// Handle form inputs
-		if m.mode == ModeCreateProject || m.mode == ModeCreateTask || m.mode == ModeEditProject || m.mode == ModeEditTask || m.mode == ModeCreateNote {
+		if m.mode == ModeCreateProject || m.mode == ModeCreateTask || m.mode == ModeEditProject || m.mode == ModeEditTask || m.mode == ModeCreateNote || m.mode == ModeCreateDraft || m.mode == ModeEditDraft {
 			switch msg.String() {
 			case "esc":
 				m.mode = ModeNormal
@@ -777,6 +1328,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, m.updateTask
 				} else if m.mode == ModeCreateNote {
 					return m, m.createNote
+				} else if m.mode == ModeCreateDraft {
+					return m, m.createDraft
+				} else if m.mode == ModeEditDraft {
+					return m, m.updateDraft
 				}
 
 			case "tab", "down":
@@ -801,6 +1356,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// The board selects and moves cards with its own keys
+		if m.board {
+			if model, cmd, ok := m.updateBoard(msg); ok {
+				return model, cmd
+			}
+		}
+
 		// Cool, what was the actual key pressed?
 		switch msg.String() {
 
@@ -821,7 +1383,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Navigate tasks
 				if m.selectedTaskIndex > 0 {
 					m.selectedTaskIndex--
-					return m, m.loadNotes
+					return m, tea.Batch(m.loadNotes, m.loadDependencies)
+				}
+			} else if m.activeSection == 4 && len(m.drafts) > 0 {
+				// Navigate drafts
+				if m.selectedDraftIndex > 0 {
+					m.selectedDraftIndex--
 				}
 			}
 
@@ -836,7 +1403,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Navigate tasks
 				if m.selectedTaskIndex < len(m.tasks)-1 {
 					m.selectedTaskIndex++
-					return m, m.loadNotes
+					return m, tea.Batch(m.loadNotes, m.loadDependencies)
+				}
+			} else if m.activeSection == 4 && len(m.drafts) > 0 {
+				// Navigate drafts
+				if m.selectedDraftIndex < len(m.drafts)-1 {
+					m.selectedDraftIndex++
 				}
 			}
 
@@ -853,11 +1425,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Create new project
 				m.initProjectForm()
 			} else if m.activeSection == 1 {
-				// Create new task
+				// Create new task, unless the project is archived
+				if err := m.archivedProjectError(); err != nil {
+					return m, m.showError(errMsg{err: err})
+				}
 				m.initTaskForm()
 			} else if m.activeSection == 2 {
 				// Create new note
 				m.initNoteForm()
+			} else if m.activeSection == 4 {
+				// Capture new draft
+				m.initDraftForm()
 			}
 			return m, nil
 
@@ -870,11 +1448,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Delete item
 		case "d":
 			if m.activeSection == 0 && len(m.projects) > 0 {
-				// Delete project
-				return m, m.deleteProject
+				// Delete project, after confirmation
+				return m, m.previewDeleteProject
 			} else if m.activeSection == 1 && len(m.tasks) > 0 {
-				// Delete task
-				return m, m.deleteTask
+				// Delete task, after confirmation
+				return m, m.previewDeleteTask
+			} else if m.activeSection == 4 && len(m.drafts) > 0 {
+				// Delete draft
+				return m, m.deleteDraft
 			}
 
 		// Edit item
@@ -885,22 +1466,143 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else if m.activeSection == 1 && len(m.tasks) > 0 {
 				// Edit task
 				m.initEditTaskForm()
+			} else if m.activeSection == 4 && len(m.drafts) > 0 {
+				// Edit draft
+				m.initEditDraftForm()
 			}
 			return m, nil
 
 		// Create subtask
 		case "s":
 			if m.activeSection == 1 && len(m.tasks) > 0 {
-				// Create subtask for selected task
+				// Create subtask for selected task, unless the project is archived
+				if err := m.archivedProjectError(); err != nil {
+					return m, m.showError(errMsg{err: err})
+				}
 				m.initSubtaskForm()
 			}
 			return m, nil
 
+		// Mark a blocker, or link the selected task to the marked one
+		case "b":
+			if m.activeSection == 1 {
+				return m, m.markBlocker()
+			}
+			return m, nil
+
+		// Make the selected task a subtask of the one above it
+		case ">":
+			if m.activeSection == 1 {
+				return m, m.indentTask
+			}
+			return m, nil
+
+		// Make the selected subtask a sibling of its parent
+		case "<":
+			if m.activeSection == 1 {
+				return m, m.outdentTask
+			}
+			return m, nil
+
+		// Move the selected task up or down among its siblings
+		case "K":
+			if m.activeSection == 1 {
+				return m, func() tea.Msg { return m.reorderTask(-1) }
+			}
+			return m, nil
+		case "J":
+			if m.activeSection == 1 {
+				return m, func() tea.Msg { return m.reorderTask(1) }
+			}
+			return m, nil
+
+		// Move the selected task to the next or previous status
+		case "]":
+			if m.activeSection == 1 {
+				return m, func() tea.Msg { return m.cycleStatus(1) }
+			}
+			return m, nil
+		case "[":
+			if m.activeSection == 1 {
+				return m, func() tea.Msg { return m.cycleStatus(-1) }
+			}
+			return m, nil
+
+		// Move the selected task to another project
+		case "m":
+			if m.activeSection == 1 {
+				return m, m.loadMoveTargets
+			}
+			return m, nil
+
+		// Clear the blocker mark
+		case "esc":
+			m.blocker = nil
+			return m, nil
+
+		// Archive or unarchive project
+		case "a":
+			if m.activeSection == 0 && len(m.projects) > 0 {
+				return m, m.toggleProjectArchived
+			}
+			return m, nil
+
+		// Cycle project filter
+		case "f":
+			if m.activeSection == 0 {
+				m.projectFilter = (m.projectFilter + 1) % 3
+				m.selectedProjectIndex = 0
+				return m, m.loadProjects
+			}
+			return m, nil
+
+		// Toggle ordering projects by due date, or tasks by hand
+		case "o":
+			if m.activeSection == 0 {
+				m.sortProjectsByDue = !m.sortProjectsByDue
+				m.selectedProjectIndex = 0
+				return m, m.loadProjects
+			} else if m.activeSection == 1 {
+				return m, m.toggleTaskOrder
+			}
+			return m, nil
+
+		// Promote draft
+		case "p":
+			if m.activeSection == 4 && len(m.drafts) > 0 {
+				m.mode = ModePromoteDraft
+			}
+			return m, nil
+
+		// Switch between the grid and the board
+		case "v":
+			return m.toggleBoard()
+
 		// Show help
 		case "?":
 			m.mode = ModeHelp
 			return m, nil
 
+		// Undo last deletion
+		case "u":
+			if m.undo != nil {
+				return m, m.undoDelete
+			}
+			return m, nil
+
+		// Show trash
+		case "T":
+			m.mode = ModeTrash
+			m.selectedTrashIndex = 0
+			m.confirmTrashDelete = false
+			return m, m.loadTrash
+
+		// Show error log
+		case "L":
+			m.mode = ModeErrorLog
+			m.statusError = ""
+			return m, nil
+
 		// Direct section navigation
 		case "1":
 			m.activeSection = 0 // Projects
@@ -968,7 +1670,11 @@ func (m Model) View() string {
 	statusBar := StatusBar(m)
 
 	// Section/Container to span height
-	content := base.Width(m.width).Height(m.height - lipgloss.Height(statusBar)).Render(Grid(m))
+	layout := Grid
+	if m.board {
+		layout = Board
+	}
+	content := base.Width(m.width).Height(m.height - lipgloss.Height(statusBar)).Render(layout(m))
 
 	// Combine content and status bar
 	board := lipgloss.JoinVertical(lipgloss.Left, content, statusBar)
@@ -987,6 +1693,31 @@ func (m Model) View() string {
 		return RenderHelp(m)
 	}
 
+	// If viewing the trash, show it
+	if m.mode == ModeTrash {
+		return RenderTrash(m)
+	}
+
+	// If confirming a delete, show the confirmation
+	if m.mode == ModeConfirmDelete {
+		return RenderConfirmDelete(m)
+	}
+
+	// If viewing the error log, show it
+	if m.mode == ModeErrorLog {
+		return RenderErrorLog(m)
+	}
+
+	// If moving a task, show the project picker
+	if m.mode == ModeMoveTask {
+		return RenderMoveTask(m)
+	}
+
+	// If promoting a draft, show the promote dialog
+	if m.mode == ModePromoteDraft {
+		return RenderPromote(m)
+	}
+
 	// If in form mode, overlay the form
 	if m.mode != ModeNormal {
 		return RenderForm(m)
@@ -994,3 +1725,113 @@ func (m Model) View() string {
 
 	return mainView
 }
+
+// parseFormPriority parses the priority field of a task form, returning
+// fallback when it is empty
+func parseFormPriority(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+
+	p, err := strconv.Atoi(value)
+	if err != nil || p < models.PriorityNone || p > models.PriorityUrgent {
+		return 0, fmt.Errorf("invalid priority %q (expected 0-4)", value)
+	}
+	return p, nil
+}
+
+// parseFormDate parses the due date field of a project form into the
+// YYYY-MM-DD form the database stores, or nil when it is empty
+func parseFormDate(value string) (*string, error) {
+	date, err := parseFormTime(value)
+	if err != nil || date == nil {
+		return nil, err
+	}
+
+	formatted := date.Format(dates.DateLayout)
+	return &formatted, nil
+}
+
+// parseFormTime parses a date field such as "tomorrow 3pm", returning nil
+// when it is empty
+func parseFormTime(value string) (*time.Time, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	date, err := dates.Parse(value, time.Now(), time.Local)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+// parseFormDates parses the due, start and scheduled fields of a task form
+func (m Model) parseFormDates() (models.TaskDates, error) {
+	var dates models.TaskDates
+	fields := []struct {
+		name   string
+		target **time.Time
+	}{
+		{"due", &dates.DueAt},
+		{"start", &dates.StartAt},
+		{"scheduled", &dates.ScheduledFor},
+	}
+	for i, field := range fields {
+		t, err := parseFormTime(m.formInputs[3+i].Value())
+		if err != nil {
+			return models.TaskDates{}, fmt.Errorf("%s date: %w", field.name, err)
+		}
+		*field.target = t
+	}
+
+	// Only the day of a scheduled date matters
+	if dates.ScheduledFor != nil {
+		day := time.Date(dates.ScheduledFor.Year(), dates.ScheduledFor.Month(), dates.ScheduledFor.Day(), 0, 0, 0, 0, time.Local)
+		dates.ScheduledFor = &day
+	}
+
+	return dates, nil
+}
+
+// parseFormRepeat parses the repeat field of a task form into its stored
+// rule, or nil when it is empty
+func parseFormRepeat(value string) (*string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	rule, err := recurrence.ParseText(value)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := rule.String()
+	return &stored, nil
+}
+
+// newRepeatInput creates the repeat field of a task form, filled with the
+// task's rule when it recurs
+func newRepeatInput(value sql.NullString) textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "Repeat (optional, e.g. daily, weekly on mon, wed)"
+	if rule, err := recurrence.Parse(value.String); value.Valid && err == nil {
+		input.SetValue(rule.Describe())
+	}
+	input.CharLimit = 60
+	input.Width = 50
+	return input
+}
+
+// newDateInput creates a date field of a task form, filled with value when
+// it is set
+func newDateInput(placeholder string, value sql.NullTime) textinput.Model {
+	input := textinput.New()
+	input.Placeholder = placeholder
+	if value.Valid {
+		input.SetValue(models.FormatTaskDate(value.Time))
+	}
+	input.CharLimit = 40
+	input.Width = 50
+	return input
+}
@@ -0,0 +1,228 @@
+package ui
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"palco/internal/database"
+	"palco/internal/database/models"
+	"palco/internal/dates"
+	"palco/internal/repository"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newTestModel returns a model on a fresh in-memory database, sized like a
+// terminal
+func newTestModel(t *testing.T) Model {
+	t.Helper()
+
+	db, err := database.New(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := database.RunMigrations(db, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	return Model{
+		Db:             db,
+		ProjectRepo:    repository.NewProjectRepository(db.DB),
+		TaskRepo:       repository.NewTaskRepository(db.DB),
+		NoteRepo:       repository.NewNoteRepository(db.DB),
+		DraftRepo:      repository.NewDraftRepository(db.DB),
+		WorkspaceRepo:  repository.NewWorkspaceRepository(db.DB),
+		TrashRepo:      repository.NewTrashRepository(db.DB),
+		DependencyRepo: repository.NewDependencyRepository(db.DB),
+		StatusRepo:     repository.NewStatusRepository(db.DB),
+		width:          160,
+		height:         40,
+	}
+}
+
+// send runs msg through Update along with every message its commands
+// produce, the way the program would. Timers are dropped.
+func send(t *testing.T, m Model, msg tea.Msg) Model {
+	t.Helper()
+
+	queue := []tea.Msg{msg}
+	for n := 0; len(queue) > 0; n++ {
+		if n > 500 {
+			t.Fatal("too many messages, the model keeps producing commands")
+		}
+		msg, queue = queue[0], queue[1:]
+
+		switch msg := msg.(type) {
+		case nil, clearErrorMsg, clearNoticeMsg:
+			continue
+		case tea.BatchMsg:
+			for _, cmd := range msg {
+				if cmd != nil {
+					queue = append(queue, runCmd(cmd))
+				}
+			}
+			continue
+		}
+
+		model, cmd := m.Update(msg)
+		m = model.(Model)
+		if cmd != nil {
+			queue = append(queue, runCmd(cmd))
+		}
+	}
+	return m
+}
+
+// press sends each key in turn
+func press(t *testing.T, m Model, keys ...string) Model {
+	t.Helper()
+	for _, k := range keys {
+		m = send(t, m, keyMsg(k))
+	}
+	return m
+}
+
+func keyMsg(k string) tea.KeyMsg {
+	switch k {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case " ":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+// runCmd runs a command, giving up on ones that wait, like timers
+func runCmd(cmd tea.Cmd) tea.Msg {
+	result := make(chan tea.Msg, 1)
+	go func() { result <- cmd() }()
+	select {
+	case msg := <-result:
+		return msg
+	case <-time.After(200 * time.Millisecond):
+		return nil
+	}
+}
+
+func TestLoadErrorsClearLists(t *testing.T) {
+	m := newTestModel(t)
+	project, err := m.ProjectRepo.Create("Home", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, err := m.TaskRepo.Create(project.ID, nil, "Paint", nil, 0, models.TaskDates{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.NoteRepo.CreateForTask(task.ID, "Matte"); err != nil {
+		t.Fatal(err)
+	}
+
+	m = send(t, m, m.loadProjects())
+	if len(m.tasks) != 1 || len(m.notes) != 1 {
+		t.Fatalf("loaded %d tasks and %d notes, want 1 and 1", len(m.tasks), len(m.notes))
+	}
+
+	// Lists from before the failure would belong to whatever was selected
+	// then, so a failed load shows the error and empties them
+	m.Db.Close()
+
+	tests := []struct {
+		name  string
+		load  tea.Cmd
+		count func(Model) int
+	}{
+		{"tasks", m.loadTasks, func(m Model) int { return len(m.tasks) }},
+		{"notes", m.loadNotes, func(m Model) int { return len(m.notes) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := send(t, m, tt.load())
+			if n := tt.count(got); n != 0 {
+				t.Errorf("%d %s left after a failed load, want none", n, tt.name)
+			}
+			if got.statusError == "" {
+				t.Error("no error shown")
+			}
+		})
+	}
+}
+
+func TestEmptyProjectFilterClearsLists(t *testing.T) {
+	m := newTestModel(t)
+	project, err := m.ProjectRepo.Create("Home", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, err := m.TaskRepo.Create(project.ID, nil, "Paint", nil, 0, models.TaskDates{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.NoteRepo.CreateForTask(task.ID, "Matte"); err != nil {
+		t.Fatal(err)
+	}
+
+	m = send(t, m, m.loadProjects())
+	if len(m.tasks) != 1 {
+		t.Fatalf("loaded %d tasks, want 1", len(m.tasks))
+	}
+
+	// No project is archived
+	m.activeSection = 0
+	m = press(t, m, "f")
+	if m.projectFilter != ProjectFilterArchived {
+		t.Fatalf("filter = %d, want archived", m.projectFilter)
+	}
+	if len(m.projects) != 0 || len(m.tasks) != 0 || len(m.notes) != 0 {
+		t.Fatalf("%d projects, %d tasks and %d notes listed, want none", len(m.projects), len(m.tasks), len(m.notes))
+	}
+
+	// Task keys have nothing to act on
+	m.activeSection = 1
+	m = press(t, m, " ")
+	_ = m.View()
+
+	got, err := m.TaskRepo.GetByID(task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Completed {
+		t.Error("the hidden project's task was completed")
+	}
+}
+
+func TestDateInputsFitPhrases(t *testing.T) {
+	phrase := "next wednesday at 3:30pm" // Longer than the old limit of 20
+	if _, err := dates.Parse(phrase, time.Now(), time.Local); err != nil {
+		t.Fatalf("%q is not a date: %v", phrase, err)
+	}
+
+	m := newTestModel(t)
+	m.initProjectForm()
+	inputs := map[string]textinput.Model{
+		"task date":        newDateInput("", sql.NullTime{}),
+		"project due date": m.formInputs[2],
+	}
+
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			input.Focus()
+			for _, r := range phrase {
+				input, _ = input.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+			}
+			if got := input.Value(); got != phrase {
+				t.Errorf("typed %q, input holds %q", phrase, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package ui
+
+import (
+	"fmt"
+
+	"palco/internal/database/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type moveTargetsLoadedMsg struct {
+	projects []models.Project
+}
+
+type taskMovedMsg struct {
+	task models.Task
+}
+
+type taskOrderChangedMsg struct {
+	project models.Project
+}
+
+// loadMoveTargets loads the projects the selected task can move to, the
+// active ones other than its own
+func (m Model) loadMoveTargets() tea.Msg {
+	if len(m.tasks) == 0 || m.selectedTaskIndex >= len(m.tasks) {
+		return nil
+	}
+
+	projects, err := m.ProjectRepo.GetAllActive()
+	if err != nil {
+		return fail(err)
+	}
+
+	currentID := m.tasks[m.selectedTaskIndex].ProjectID.Int64
+	targets := []models.Project{}
+	for _, project := range projects {
+		if project.ID != currentID {
+			targets = append(targets, project)
+		}
+	}
+	if len(targets) == 0 {
+		return invalid("no other active project to move the task to")
+	}
+
+	return moveTargetsLoadedMsg{projects: targets}
+}
+
+// moveTask moves the selected task with its subtasks to the top level of
+// the project picked in the move dialog
+func (m Model) moveTask() tea.Msg {
+	if len(m.tasks) == 0 || m.selectedTaskIndex >= len(m.tasks) || m.selectedMoveIndex >= len(m.moveTargets) {
+		return nil
+	}
+
+	task, err := m.TaskRepo.Move(m.tasks[m.selectedTaskIndex].ID, m.moveTargets[m.selectedMoveIndex].ID, nil)
+	if err != nil {
+		return fail(err)
+	}
+	return taskMovedMsg{task: *task}
+}
+
+// indentTask makes the selected task a subtask of the task above it at the
+// same level
+func (m Model) indentTask() tea.Msg {
+	if len(m.tasks) == 0 || m.selectedTaskIndex >= len(m.tasks) {
+		return nil
+	}
+
+	i := m.selectedTaskIndex
+	depth := m.taskDepths[i]
+
+	// Walk up past the subtasks of the sibling above; reaching the parent
+	// first means there is no sibling to indent under
+	for j := i - 1; j >= 0 && m.taskDepths[j] >= depth; j-- {
+		if m.taskDepths[j] == depth {
+			return m.moveUnder(m.tasks[i], &m.tasks[j].ID)
+		}
+	}
+	return invalid("no task above %q at its level to indent it under", m.tasks[i].Title)
+}
+
+// outdentTask makes the selected subtask a sibling of its parent
+func (m Model) outdentTask() tea.Msg {
+	if len(m.tasks) == 0 || m.selectedTaskIndex >= len(m.tasks) {
+		return nil
+	}
+
+	task := m.tasks[m.selectedTaskIndex]
+	if !task.ParentTaskID.Valid {
+		return invalid("%q is already a top level task", task.Title)
+	}
+
+	for _, parent := range m.tasks {
+		if parent.ID != task.ParentTaskID.Int64 {
+			continue
+		}
+
+		var grandparentID *int64
+		if parent.ParentTaskID.Valid {
+			grandparentID = &parent.ParentTaskID.Int64
+		}
+		return m.moveUnder(task, grandparentID)
+	}
+	return nil
+}
+
+// moveUnder moves task under parentID within its project
+func (m Model) moveUnder(task models.Task, parentID *int64) tea.Msg {
+	if err := m.archivedProjectError(); err != nil {
+		return fail(err)
+	}
+
+	moved, err := m.TaskRepo.Move(task.ID, task.ProjectID.Int64, parentID)
+	if err != nil {
+		return fail(err)
+	}
+	return taskMovedMsg{task: *moved}
+}
+
+// reorderTask moves the selected task offset places among its siblings
+func (m Model) reorderTask(offset int) tea.Msg {
+	if len(m.tasks) == 0 || m.selectedTaskIndex >= len(m.tasks) {
+		return nil
+	}
+	if len(m.projects) == 0 || m.selectedProjectIndex >= len(m.projects) {
+		return nil
+	}
+
+	project := m.projects[m.selectedProjectIndex]
+	if !project.ManualOrder {
+		return invalid("%q lists its tasks by priority, press o to order them by hand", project.Name)
+	}
+
+	task, err := m.TaskRepo.Reorder(m.tasks[m.selectedTaskIndex].ID, offset)
+	if err != nil {
+		return fail(err)
+	}
+	return taskMovedMsg{task: *task}
+}
+
+// toggleTaskOrder switches the selected project between manual and
+// priority task order
+func (m Model) toggleTaskOrder() tea.Msg {
+	if len(m.projects) == 0 || m.selectedProjectIndex >= len(m.projects) {
+		return nil
+	}
+
+	project := m.projects[m.selectedProjectIndex]
+	project.ManualOrder = !project.ManualOrder
+	if err := m.ProjectRepo.SetManualOrder(project.ID, project.ManualOrder); err != nil {
+		return fail(err)
+	}
+	return taskOrderChangedMsg{project: project}
+}
+
+// updateMoveTask handles keys in the move dialog
+func (m Model) updateMoveTask(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.mode = ModeNormal
+	case "up", "k":
+		if m.selectedMoveIndex > 0 {
+			m.selectedMoveIndex--
+		}
+	case "down", "j":
+		if m.selectedMoveIndex < len(m.moveTargets)-1 {
+			m.selectedMoveIndex++
+		}
+	case "enter":
+		return m, m.moveTask
+	}
+	return m, nil
+}
+
+// RenderMoveTask lists the projects the selected task can move to
+func RenderMoveTask(m Model) string {
+	if len(m.tasks) == 0 || m.selectedTaskIndex >= len(m.tasks) {
+		return ""
+	}
+
+	task := m.tasks[m.selectedTaskIndex]
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(highlight).
+		MarginBottom(1)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(highlight)
+
+	title := task.Title
+	if n := subtaskCount(m); n > 0 {
+		title += fmt.Sprintf(" (with %s)", plural(n, "subtask"))
+	}
+
+	parts := []string{
+		titleStyle.Render("Move Task"),
+		wrapText(title, 50),
+		"",
+	}
+
+	// Keep the selected project in view when the list is taller than the screen
+	limit := max(m.height-16, 1)
+	start := 0
+	if m.selectedMoveIndex >= limit {
+		start = m.selectedMoveIndex - limit + 1
+	}
+
+	for i := start; i < len(m.moveTargets) && i < start+limit; i++ {
+		line := "  " + m.moveTargets[i].Name
+		if i == m.selectedMoveIndex {
+			line = selectedStyle.Render("> " + m.moveTargets[i].Name)
+		}
+		parts = append(parts, line)
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(subtle).
+		MarginTop(1)
+	parts = append(parts, helpStyle.Render("Enter: Move • ↑↓: Navigate • Esc: Cancel"))
+	if errorLine := renderInlineError(m, 50); errorLine != "" {
+		parts = append(parts, errorLine)
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(highlight).
+		Padding(2, 4).
+		Width(60).
+		Render(lipgloss.JoinVertical(lipgloss.Left, parts...))
+
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		box,
+	)
+}
+
+// subtaskCount counts the subtasks that move with the selected task
+func subtaskCount(m Model) int {
+	depth := m.taskDepths[m.selectedTaskIndex]
+	n := 0
+	for j := m.selectedTaskIndex + 1; j < len(m.tasks) && m.taskDepths[j] > depth; j++ {
+		n++
+	}
+	return n
+}
@@ -2,10 +2,15 @@ package ui
 
 import (
 	"fmt"
+	"time"
+
+	"palco/internal/database/models"
 
 	"github.com/charmbracelet/lipgloss"
 )
 
+var warningColor = lipgloss.AdaptiveColor{Light: "#D78700", Dark: "#FFAF5F"}
+
 func Projects(m Model) string {
 	col1Width := int(float64(m.width) * 0.40)
 	row1Height := int(float64(m.height) * 0.30)
@@ -16,20 +21,21 @@ func Projects(m Model) string {
 		content = lipgloss.NewStyle().
 			Foreground(subtle).
 			Padding(1).
-			Render("No projects found")
+			Render(emptyProjectsText(m.projectFilter))
 	} else {
 		content = renderProjectList(m)
 	}
 
 	return Section(m.activeSection == 0).Width(col1Width).Height(row1Height - 2).Render(
 		lipgloss.JoinVertical(lipgloss.Left,
-			listHeader("Projects [1]"),
+			listHeader(projectsHeader(m)),
 			content,
 		),
 	)
 }
 
 func renderProjectList(m Model) string {
+	now := time.Now()
 	items := make([]string, len(m.projects))
 	for i, project := range m.projects {
 		cursor := " "
@@ -42,8 +48,75 @@ func renderProjectList(m Model) string {
 			name = name[:22] + "..."
 		}
 
+		// Archived projects are dimmed; the marker tells them apart in "All"
+		if project.Archived {
+			name = lipgloss.NewStyle().Foreground(subtle).Render(name + " (archived)")
+		} else if days, ok := project.DaysUntilDue(now); ok && days <= models.DueSoonDays {
+			name += " " + lipgloss.NewStyle().Foreground(dueColor(days)).Render(dueText(days))
+		}
+
 		items[i] = fmt.Sprintf("%s %s", cursor, name)
 	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, items...)
 }
+
+// projectsHeader names the panel with its filter and, when sorted by due
+// date, its ordering
+func projectsHeader(m Model) string {
+	header := "Projects [1] · " + projectFilterName(m.projectFilter)
+	if m.sortProjectsByDue {
+		header += " · by due date"
+	}
+	return header
+}
+
+// dueText describes a due date relative to today, e.g. "due in 3 days"
+func dueText(days int) string {
+	switch {
+	case days < -1:
+		return fmt.Sprintf("overdue by %d days", -days)
+	case days == -1:
+		return "overdue by 1 day"
+	case days == 0:
+		return "due today"
+	case days == 1:
+		return "due tomorrow"
+	default:
+		return fmt.Sprintf("due in %d days", days)
+	}
+}
+
+// dueColor is red for overdue projects and amber for ones due soon
+func dueColor(days int) lipgloss.TerminalColor {
+	switch {
+	case days < 0:
+		return errorColor
+	case days <= models.DueSoonDays:
+		return warningColor
+	default:
+		return lipgloss.NoColor{}
+	}
+}
+
+// projectFilterName names a project filter for the panel header
+func projectFilterName(filter int) string {
+	switch filter {
+	case ProjectFilterArchived:
+		return "Archived"
+	case ProjectFilterAll:
+		return "All"
+	default:
+		return "Active"
+	}
+}
+
+// emptyProjectsText is shown when no project matches the filter
+func emptyProjectsText(filter int) string {
+	switch filter {
+	case ProjectFilterArchived:
+		return "No archived projects"
+	default:
+		return "No projects found"
+	}
+}
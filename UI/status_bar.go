@@ -29,6 +29,14 @@ var (
 	statusText = lipgloss.NewStyle().Inherit(statusBarStyle)
 
 	fishCakeStyle = statusNugget.Background(lipgloss.Color("#6124DF"))
+
+	errorNugget = statusNugget.
+			Background(lipgloss.Color("#D70000")).
+			MarginRight(1)
+
+	errorText = lipgloss.NewStyle().
+			Inherit(statusBarStyle).
+			Foreground(errorColor)
 )
 
 func StatusBar(m Model) string {
@@ -50,6 +58,10 @@ func StatusBar(m Model) string {
 		sectionName = "Drafts"
 	}
 
+	if m.board {
+		sectionName = boardName(m)
+	}
+
 	statusKey := statusStyle.Render(sectionName)
 
 	// Help hint
@@ -61,22 +73,52 @@ func StatusBar(m Model) string {
 		statusMsg = "Editing..."
 	} else {
 		// Context-aware hints
-		switch m.activeSection {
-		case 0:
-			statusMsg = "n:New  e:Edit  d:Delete  ↑↓:Navigate  Tab:Switch"
-		case 1:
-			statusMsg = "n:New  s:Subtask  e:Edit  d:Delete  Space:Toggle  ↑↓:Navigate"
-		case 2:
+		switch {
+		case m.board:
+			statusMsg = "←→↑↓:Select  h/l:Move card  j/k:Reorder  n:New  e:Edit  Space:Toggle  o:Order  v:Grid"
+		case m.activeSection == 0:
+			statusMsg = "n:New  e:Edit  d:Delete  a:Archive  f:Filter  o:Sort  ↑↓:Navigate  Tab:Switch"
+		case m.activeSection == 1:
+			statusMsg = "n:New  s:Subtask  e:Edit  d:Delete  Space:Toggle  []:Status  b:Block  <>:Indent  K/J:Reorder  o:Order  m:Move"
+		case m.activeSection == 2:
 			statusMsg = "n:New Note  ↑↓:Navigate  Tab:Switch"
+		case m.activeSection == 4:
+			statusMsg = "n:Capture  e:Edit  d:Delete  p:Promote  ↑↓:Navigate"
 		default:
 			statusMsg = "Tab:Switch Sections  ?:Help  q:Quit"
 		}
 	}
 
+	// Offer undo while the last deletion can still be restored
+	if m.undo != nil && m.mode == ModeNormal {
+		statusMsg = "Deleted " + m.undo.deleted.label() + "  u:Undo  T:Trash"
+	}
+
+	// Guide linking while a blocker is marked
+	if m.blocker != nil && m.mode == ModeNormal {
+		statusMsg = "Blocker: " + m.blocker.Title + "  b:Link/unlink selected task  Esc:Cancel"
+	}
+
+	// Notices replace the hints until they time out
+	if m.statusNotice != "" && m.mode == ModeNormal {
+		statusMsg = m.statusNotice
+	}
+
 	statusVal := statusText.
 		Width(m.width - w(statusKey) - w(helpHint) - 4).
+		MaxHeight(1).
 		Render(statusMsg)
 
+	// Errors replace the hints until they time out
+	if m.statusError != "" {
+		errorKey := errorNugget.Render("Error")
+		statusVal = errorText.
+			Width(m.width - w(statusKey) - w(errorKey) - w(helpHint) - 4).
+			MaxHeight(1).
+			Render(m.statusError + "  (L: Log)")
+		statusKey = lipgloss.JoinHorizontal(lipgloss.Top, statusKey, errorKey)
+	}
+
 	return statusBarStyle.Width(m.width).Render(
 		lipgloss.JoinHorizontal(lipgloss.Top, statusKey, statusVal, helpHint),
 	)
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"palco/internal/database/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// statusLabelColor marks tasks in a status other than the default one
+var statusLabelColor = lipgloss.AdaptiveColor{Light: "#5A56E0", Dark: "#A49FF7"}
+
+type taskStatusChangedMsg struct {
+	task      models.Task
+	status    models.Status
+	unblocked []models.Task // Tasks the completion freed up
+}
+
+// cycleStatus moves the selected task step statuses along its project's
+// workflow, skipping statuses the workflow does not allow moving to
+func (m Model) cycleStatus(step int) tea.Msg {
+	if len(m.tasks) == 0 || m.selectedTaskIndex >= len(m.tasks) || len(m.workflow) == 0 {
+		return nil
+	}
+
+	task := m.tasks[m.selectedTaskIndex]
+	current := m.workflow.Of(task)
+	next, ok := m.workflow.Cycle(current, step)
+	if !ok {
+		return invalid("%q cannot move out of %s", task.Title, current.Name)
+	}
+
+	return m.setTaskStatus(task, next)
+}
+
+// setTaskStatus moves task to status. Reaching a done status completes the
+// task like toggling it does.
+func (m Model) setTaskStatus(task models.Task, status models.Status) tea.Msg {
+	completion, err := m.TaskRepo.SetStatus(task.ID, status.ID, time.Now())
+	if err != nil {
+		return fail(err)
+	}
+
+	return taskStatusChangedMsg{task: *completion.Task, status: status, unblocked: completion.Unblocked}
+}
+
+// statusNotice describes a status change and the tasks it freed up
+func statusNotice(msg taskStatusChangedMsg) string {
+	notice := fmt.Sprintf("%s → %s", msg.task.Title, msg.status.Name)
+	if len(msg.unblocked) > 0 {
+		notice += " · " + unblockedNotice(msg.unblocked)
+	}
+	return notice
+}
+
+// renderStatusLabel renders the status shown after a task's title, empty
+// when its checkbox already tells it
+func renderStatusLabel(m Model, task models.Task) string {
+	label := m.workflow.Label(task)
+	if label == "" {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(statusLabelColor).Render(label)
+}
+
+// renderStatusDetails renders the status of the selected task for the
+// details panel, with the statuses it can move to when the workflow limits
+// them
+func renderStatusDetails(m Model, task models.Task) []string {
+	labelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(special)
+
+	checkbox := "[ ]"
+	if task.Completed {
+		checkbox = "[✓]"
+	}
+
+	status := m.workflow.Of(task)
+	if status.Name == "" {
+		if task.Completed {
+			return []string{labelStyle.Render("Status: ") + checkbox + " Completed"}
+		}
+		return []string{labelStyle.Render("Status: ") + checkbox + " Incomplete"}
+	}
+
+	parts := []string{labelStyle.Render("Status: ") + checkbox + " " + status.Name}
+	if len(status.Next) > 0 {
+		var names []string
+		for _, id := range status.Next {
+			if next, ok := m.workflow.Find(id); ok {
+				names = append(names, next.Name)
+			}
+		}
+		parts = append(parts, labelStyle.Render("Moves to: ")+strings.Join(names, ", "))
+	}
+	return parts
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -28,13 +29,22 @@ func Tasks(m Model) string {
 
 	return Section(m.activeSection == 1).Width(col1Width).Height(row2Height - 2).Render(
 		lipgloss.JoinVertical(lipgloss.Left,
-			listHeader("Tasks [2]"),
+			listHeader(tasksHeader(m)),
 			content,
 		),
 	)
 }
 
+// tasksHeader names the panel with its ordering when it is manual
+func tasksHeader(m Model) string {
+	if len(m.projects) > 0 && m.projects[m.selectedProjectIndex].ManualOrder {
+		return "Tasks [2] · manual order"
+	}
+	return "Tasks [2]"
+}
+
 func renderTaskList(m Model) string {
+	now := time.Now()
 	items := make([]string, len(m.tasks))
 	for i, task := range m.tasks {
 		cursor := " "
@@ -71,6 +81,41 @@ func renderTaskList(m Model) string {
 			status = "[✓]"
 		}
 
+		// Mark recurring tasks
+		if task.Recurrence.Valid {
+			title += " ↻"
+		}
+
+		// Name the status when the checkbox does not tell it
+		if label := renderStatusLabel(m, task); label != "" {
+			title += " " + label
+		}
+
+		// Dim open tasks that wait on open blockers
+		blocked := m.blockedTasks[task.ID] && !task.Completed
+		if blocked {
+			style := lipgloss.NewStyle().Foreground(blockedColor)
+			title = style.Render(title) + " " + style.Bold(true).Render("blocked")
+		}
+
+		// Highlight open tasks that are overdue or due today
+		if days, ok := task.DaysUntilDue(now); ok && !task.Completed && days <= 0 {
+			style := lipgloss.NewStyle().Foreground(dueColor(days))
+			label := "today"
+			if days < 0 {
+				label = "overdue"
+			}
+			if !blocked {
+				title = style.Render(title)
+			}
+			title += " " + style.Bold(true).Render(label)
+		}
+
+		// Show the task marked with b
+		if m.blocker != nil && m.blocker.ID == task.ID {
+			title += " " + lipgloss.NewStyle().Foreground(highlight).Bold(true).Render("◆ blocker")
+		}
+
 		items[i] = fmt.Sprintf("%s %s%s%s %s", cursor, indent, prefix, status, title)
 	}
 
@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"palco/internal/database/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type trashLoadedMsg struct {
+	items []models.TrashItem
+}
+
+type trashDeletedMsg struct{}
+
+// loadTrash loads the items in the trash
+func (m Model) loadTrash() tea.Msg {
+	items, err := m.TrashRepo.GetAll()
+	if err != nil {
+		return fail(err)
+	}
+	return trashLoadedMsg{items: items}
+}
+
+// restoreTrashItem restores the selected trash item with everything that
+// was deleted with it
+func (m Model) restoreTrashItem() tea.Msg {
+	if len(m.trash) == 0 || m.selectedTrashIndex >= len(m.trash) {
+		return nil
+	}
+
+	item := m.trash[m.selectedTrashIndex]
+	if err := m.TrashRepo.Restore(item.Kind, item.ID); err != nil {
+		return fail(err)
+	}
+
+	return restoredMsg{project: item.Kind == models.TrashProject}
+}
+
+// deleteTrashItem permanently deletes the selected trash item
+func (m Model) deleteTrashItem() tea.Msg {
+	if len(m.trash) == 0 || m.selectedTrashIndex >= len(m.trash) {
+		return nil
+	}
+
+	item := m.trash[m.selectedTrashIndex]
+	if err := m.TrashRepo.Delete(item.Kind, item.ID); err != nil {
+		return fail(err)
+	}
+
+	return trashDeletedMsg{}
+}
+
+// updateTrash handles keys in the trash view
+func (m Model) updateTrash(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Permanent delete needs a second key press
+	if m.confirmTrashDelete {
+		m.confirmTrashDelete = false
+		if msg.String() == "y" || msg.String() == "Y" {
+			return m, m.deleteTrashItem
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "T", "q":
+		m.mode = ModeNormal
+	case "up", "k":
+		if m.selectedTrashIndex > 0 {
+			m.selectedTrashIndex--
+		}
+	case "down", "j":
+		if m.selectedTrashIndex < len(m.trash)-1 {
+			m.selectedTrashIndex++
+		}
+	case "r", "enter":
+		return m, m.restoreTrashItem
+	case "D":
+		if len(m.trash) > 0 {
+			m.confirmTrashDelete = true
+		}
+	}
+	return m, nil
+}
+
+// RenderTrash lists deleted items, newest first
+func RenderTrash(m Model) string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(highlight).
+		MarginBottom(1)
+
+	kindStyle := lipgloss.NewStyle().
+		Foreground(special).
+		Width(9)
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(subtle)
+
+	parts := []string{titleStyle.Render(fmt.Sprintf("Trash (%d)", len(m.trash)))}
+
+	if len(m.trash) == 0 {
+		parts = append(parts, dimStyle.Render("The trash is empty"))
+	}
+
+	// Keep the selected item in view when the list is taller than the screen
+	limit := max(m.height-14, 1)
+	start := 0
+	if m.selectedTrashIndex >= limit {
+		start = m.selectedTrashIndex - limit + 1
+	}
+
+	for i := start; i < len(m.trash) && i < start+limit; i++ {
+		item := m.trash[i]
+
+		cursor := " "
+		if i == m.selectedTrashIndex {
+			cursor = ">"
+		}
+
+		title, _, _ := strings.Cut(item.Title, "\n")
+		if len(title) > 40 {
+			title = title[:37] + "..."
+		}
+
+		var details []string
+		if item.Project != "" && item.Kind != models.TrashProject {
+			details = append(details, "in "+item.Project)
+		}
+		if item.Tasks > 0 {
+			noun := "task"
+			if item.Kind == models.TrashTask {
+				noun = "subtask"
+			}
+			details = append(details, plural(item.Tasks, noun))
+		}
+		if item.Notes > 0 {
+			details = append(details, plural(item.Notes, "note"))
+		}
+		details = append(details, item.DeletedAt.Local().Format("Jan 2 15:04"))
+
+		parts = append(parts, fmt.Sprintf("%s %s%s %s",
+			cursor,
+			kindStyle.Render(item.Kind),
+			title,
+			dimStyle.Render("· "+strings.Join(details, " · ")),
+		))
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(subtle).
+		MarginTop(1)
+
+	help := "r/Enter: Restore • D: Delete permanently • ↑↓: Navigate • Esc/T: Close"
+	if m.confirmTrashDelete {
+		help = lipgloss.NewStyle().Foreground(errorColor).Render("Delete permanently? This cannot be undone. y: Delete • any other key: Cancel")
+	}
+	parts = append(parts, helpStyle.Render(help))
+
+	if errorLine := renderInlineError(m, 80); errorLine != "" {
+		parts = append(parts, errorLine)
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(highlight).
+		Padding(1, 2).
+		Width(min(100, m.width-4)).
+		Render(lipgloss.JoinVertical(lipgloss.Left, parts...))
+
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		box,
+	)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"palco/internal/capture"
+	"palco/internal/database/models"
+)
+
+// runCapture records text in the drafts inbox, or files it straight as a
+// task when it names an existing +project. Anything that cannot be filed
+// is kept as a draft so no idea is lost.
+func runCapture(a *app, args []string) error {
+	fs := newFlagSet("capture", "[text...]  (reads stdin when no text is given)")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	text := joinArgs(positional)
+	if text == "" || text == "-" {
+		// Without text and a pipe there is nothing to wait for
+		if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			fs.Usage()
+			return errUsage
+		}
+
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+		text = strings.TrimSpace(string(data))
+	}
+	if text == "" {
+		fs.Usage()
+		return errUsage
+	}
+
+	entry, err := capture.Parse(text, time.Now())
+	if err != nil {
+		return captureDraft(a, text, err.Error())
+	}
+	if entry.Project == "" {
+		return captureDraft(a, entry.String(), "")
+	}
+
+	project, err := a.captureProject(entry.Project)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return captureDraft(a, entry.String(), fmt.Sprintf("project %q not found", entry.Project))
+	}
+	if project.Archived {
+		return captureDraft(a, entry.String(), fmt.Sprintf("project %q is archived", project.Name))
+	}
+
+	task, err := a.taskRepo.Create(project.ID, nil, entry.Title, optional(entry.Body), entry.Priority, models.TaskDates{DueAt: entry.Due}, nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "Created task #%d %s in %s\n", task.ID, task.Title, project.Name)
+	return nil
+}
+
+// captureProject looks up a +project tag by name. Tags cannot contain
+// spaces, so "Home-Office" also matches "Home Office".
+func (a *app) captureProject(tag string) (*models.Project, error) {
+	project, err := a.projectRepo.GetByName(tag)
+	if err != nil || project != nil {
+		return project, err
+	}
+	if !strings.Contains(tag, "-") {
+		return nil, nil
+	}
+	return a.projectRepo.GetByName(strings.ReplaceAll(tag, "-", " "))
+}
+
+// captureDraft stores content in the drafts inbox, mentioning why it was
+// not filed as a task when reason is set
+func captureDraft(a *app, content, reason string) error {
+	draft, err := a.draftRepo.Create(content)
+	if err != nil {
+		return err
+	}
+
+	if reason != "" {
+		fmt.Fprintf(a.out, "Captured draft #%d (%s)\n", draft.ID, reason)
+	} else {
+		fmt.Fprintf(a.out, "Captured draft #%d\n", draft.ID)
+	}
+	return nil
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"palco/internal/database"
+	"palco/internal/database/models"
+	"palco/internal/dates"
+	"palco/internal/recurrence"
+	"palco/internal/repository"
+)
+
+// app bundles what every subcommand needs
+type app struct {
+	db  *database.DB
+	out io.Writer
+
+	projectRepo    *repository.ProjectRepository
+	taskRepo       *repository.TaskRepository
+	noteRepo       *repository.NoteRepository
+	draftRepo      *repository.DraftRepository
+	trashRepo      *repository.TrashRepository
+	workspaceRepo  *repository.WorkspaceRepository
+	dependencyRepo *repository.DependencyRepository
+	statusRepo     *repository.StatusRepository
+}
+
+// command is a CLI subcommand. Commands with subcommands dispatch on
+// their first argument themselves.
+type command struct {
+	summary string
+	run     func(a *app, args []string) error
+}
+
+var commands = map[string]command{
+	"project":  {"Manage projects (list, show, add, archive, unarchive, order)", runProject},
+	"task":     {"Manage tasks (list, show, add, done, undone, status, block, unblock, move, reorder)", runTask},
+	"workflow": {"Manage the statuses of a project's tasks (show, set)", runWorkflow},
+	"note":     {"Manage notes (list, add)", runNote},
+	"trash":    {"Manage deleted items (list, restore, delete, purge)", runTrash},
+	"capture":  {"Capture text to the drafts inbox, or as a task with +project !priority @due:date", runCapture},
+	"export":   {"Export the workspace as JSON, todo.txt or CSV, or a project as Markdown", runExport},
+	"import":   {"Import a JSON workspace, Markdown checklist, todo.txt, Taskwarrior export or CSV", runImport},
+}
+
+// errUsage is returned when a command was called with bad arguments; the
+// command has already printed its usage
+var errUsage = errors.New("invalid usage")
+
+func newApp(db *database.DB) *app {
+	return &app{
+		db:  db,
+		out: os.Stdout,
+
+		projectRepo:    repository.NewProjectRepository(db.DB),
+		taskRepo:       repository.NewTaskRepository(db.DB),
+		noteRepo:       repository.NewNoteRepository(db.DB),
+		draftRepo:      repository.NewDraftRepository(db.DB),
+		trashRepo:      repository.NewTrashRepository(db.DB),
+		workspaceRepo:  repository.NewWorkspaceRepository(db.DB),
+		dependencyRepo: repository.NewDependencyRepository(db.DB),
+		statusRepo:     repository.NewStatusRepository(db.DB),
+	}
+}
+
+// runCommand dispatches args[0] to the matching subcommand
+func runCommand(opts database.Options, args []string) error {
+	if args[0] == "help" {
+		usage()
+		return nil
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		usage()
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	db := database.Run(opts)
+	defer db.Close()
+
+	return cmd.run(newApp(db), args[1:])
+}
+
+// usage prints the top level help
+func usage() {
+	w := flag.CommandLine.Output()
+	fmt.Fprintf(w, "Usage: palco [flags] [command]\n\n")
+	fmt.Fprintf(w, "Without a command palco starts the terminal UI.\n\n")
+	fmt.Fprintf(w, "Commands:\n")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].summary)
+	}
+
+	fmt.Fprintf(w, "\nFlags:\n")
+	flag.PrintDefaults()
+}
+
+// subcommand is one action of a command, e.g. "add" in "palco task add".
+// run receives a flag set already set up to print the usage line.
+type subcommand struct {
+	usage string
+	run   func(a *app, fs *flag.FlagSet, args []string) error
+}
+
+// dispatch runs the subcommand named by args[0]
+func dispatch(a *app, name string, subs map[string]subcommand, args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		subUsage(name, subs)
+		if len(args) == 0 {
+			return errUsage
+		}
+		return nil
+	}
+
+	sub, ok := subs[args[0]]
+	if !ok {
+		subUsage(name, subs)
+		return fmt.Errorf("unknown %s command %q", name, args[0])
+	}
+
+	return sub.run(a, newFlagSet(name, sub.usage), args[1:])
+}
+
+func subUsage(name string, subs map[string]subcommand) {
+	w := flag.CommandLine.Output()
+	fmt.Fprintf(w, "Usage:\n")
+
+	names := make([]string, 0, len(subs))
+	for sub := range subs {
+		names = append(names, sub)
+	}
+	sort.Strings(names)
+	for _, sub := range names {
+		fmt.Fprintf(w, "  palco %s %s\n", name, subs[sub].usage)
+	}
+}
+
+// newFlagSet creates a flag set for "palco <name>" that prints its usage
+// line on error
+func newFlagSet(name, usageLine string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: palco %s %s\n", name, usageLine)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseArgs parses flags that may appear before, between or after the
+// positional arguments and returns the positional ones
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, errUsage
+			}
+			return nil, err
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// Output formats for list and show commands
+const (
+	formatText = iota
+	formatJSON
+	formatNDJSON
+)
+
+// outputFlags registers --json and --ndjson on fs
+type outputFlags struct {
+	json   *bool
+	ndjson *bool
+}
+
+func addOutputFlags(fs *flag.FlagSet) outputFlags {
+	return outputFlags{
+		json:   fs.Bool("json", false, "print JSON"),
+		ndjson: fs.Bool("ndjson", false, "print newline-delimited JSON, one object per line"),
+	}
+}
+
+// format returns the selected output format
+func (o outputFlags) format() (int, error) {
+	switch {
+	case *o.json && *o.ndjson:
+		return 0, fmt.Errorf("--json and --ndjson are mutually exclusive")
+	case *o.json:
+		return formatJSON, nil
+	case *o.ndjson:
+		return formatNDJSON, nil
+	default:
+		return formatText, nil
+	}
+}
+
+// writeList prints items as a JSON array, or one object per line for NDJSON
+func writeList[T any](a *app, format int, items []T) error {
+	if format == formatNDJSON {
+		enc := json.NewEncoder(a.out)
+		for _, item := range items {
+			if err := enc.Encode(item); err != nil {
+				return fmt.Errorf("failed to encode JSON: %w", err)
+			}
+		}
+		return nil
+	}
+
+	if items == nil {
+		items = []T{}
+	}
+	return writeItem(a, format, items)
+}
+
+// writeItem prints a single value as indented JSON, or on one line for NDJSON
+func writeItem(a *app, format int, v any) error {
+	enc := json.NewEncoder(a.out)
+	if format == formatJSON {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return nil
+}
+
+// parseID parses a numeric entity ID
+func parseID(kind, s string) (int64, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid %s ID %q", kind, s)
+	}
+	return id, nil
+}
+
+// parsePriority parses a 0-4 priority
+func parsePriority(s string) (int, error) {
+	p, err := strconv.Atoi(s)
+	if err != nil || p < models.PriorityNone || p > models.PriorityUrgent {
+		return 0, fmt.Errorf("invalid priority %q (expected 0-4)", s)
+	}
+	return p, nil
+}
+
+// parseDate parses an optional date flag such as "2026-11-01", "friday" or
+// "tomorrow 3pm". It returns nil when s is empty.
+func parseDate(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	date, err := dates.Parse(s, time.Now(), time.Local)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+// parseRepeat parses an optional repeat rule flag such as "weekly on mon"
+// into its stored form. It returns nil when s is empty.
+func parseRepeat(s string) (*string, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	rule, err := recurrence.ParseText(s)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := rule.String()
+	return &stored, nil
+}
+
+// resolveProject finds a project by ID or by name. A number that is not a
+// project ID is looked up as a name, so projects named like "2026" resolve.
+func (a *app) resolveProject(ref string) (*models.Project, error) {
+	if ref == "" {
+		return nil, fmt.Errorf("no project given")
+	}
+
+	project, err := a.lookupProject(ref)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project %q not found", ref)
+	}
+	return project, nil
+}
+
+// lookupProject is resolveProject returning nil when no project matches ref
+func (a *app) lookupProject(ref string) (*models.Project, error) {
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		project, err := a.projectRepo.GetByID(id)
+		if !errors.Is(err, sql.ErrNoRows) {
+			return project, err
+		}
+	}
+
+	return a.projectRepo.GetByName(ref)
+}
+
+// taskTrees nests tasks under their parents and attaches each task's notes
+func (a *app) taskTrees(tasks []models.Task) ([]models.TaskTree, error) {
+	notesByTask := make(map[int64][]models.Note, len(tasks))
+	for _, task := range tasks {
+		notes, err := a.noteRepo.GetByTaskID(task.ID)
+		if err != nil {
+			return nil, err
+		}
+		notesByTask[task.ID] = notes
+	}
+
+	return models.BuildTaskTrees(tasks, notesByTask), nil
+}
+
+// projectTree loads a project with its notes and full task tree
+func (a *app) projectTree(ref string) (*models.ProjectTree, error) {
+	project, err := a.resolveProject(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := a.taskRepo.GetByProjectID(project.ID)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := a.noteRepo.GetByProjectID(project.ID)
+	if err != nil {
+		return nil, err
+	}
+	trees, err := a.taskTrees(tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ProjectTree{Project: *project, Notes: notes, Tasks: trees}, nil
+}
+
+// optional returns a pointer to s, or nil when s is empty
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// joinArgs joins positional arguments into one string so titles do not
+// need quoting
+func joinArgs(args []string) string {
+	return strings.TrimSpace(strings.Join(args, " "))
+}
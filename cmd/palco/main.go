@@ -1,6 +1,8 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 
@@ -12,21 +14,43 @@ import (
 )
 
 func main() {
-	program := tea.NewProgram(init_model(), tea.WithAltScreen())
+	dbPath := flag.String("db", "", "path to the database file (overrides PALCO_DB and the config file)")
+	migrationsDir := flag.String("migrations", "", "read SQL migrations from this directory instead of the embedded ones (development)")
+	flag.Usage = usage
+	flag.Parse()
+
+	opts := database.Options{Path: *dbPath, MigrationsDir: *migrationsDir}
+
+	if flag.NArg() > 0 {
+		if err := runCommand(opts, flag.Args()); err != nil {
+			if !errors.Is(err, errUsage) {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+			os.Exit(1)
+		}
+		return
+	}
+
+	program := tea.NewProgram(init_model(opts), tea.WithAltScreen())
 	if _, err := program.Run(); err != nil {
 		fmt.Printf("Error: %v", err)
 		os.Exit(1)
 	}
 }
 
-func init_model() ui.Model {
-	db := database.Run()
+func init_model(opts database.Options) ui.Model {
+	db := database.Run(opts)
 	return ui.Model{
 		Db: db,
 
 		// Initialize repositories
-		ProjectRepo: repository.NewProjectRepository(db.DB),
-		TaskRepo:    repository.NewTaskRepository(db.DB),
-		NoteRepo:    repository.NewNoteRepository(db.DB),
+		ProjectRepo:    repository.NewProjectRepository(db.DB),
+		TaskRepo:       repository.NewTaskRepository(db.DB),
+		NoteRepo:       repository.NewNoteRepository(db.DB),
+		DraftRepo:      repository.NewDraftRepository(db.DB),
+		WorkspaceRepo:  repository.NewWorkspaceRepository(db.DB),
+		TrashRepo:      repository.NewTrashRepository(db.DB),
+		DependencyRepo: repository.NewDependencyRepository(db.DB),
+		StatusRepo:     repository.NewStatusRepository(db.DB),
 	}
 }
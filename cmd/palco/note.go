@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"palco/internal/database/models"
+)
+
+var noteCommands = map[string]subcommand{
+	"list": {"list (--project <id|name> | --task id) [--json | --ndjson]", noteList},
+	"add":  {"add <content> (--project <id|name> | --task id)", noteAdd},
+}
+
+func runNote(a *app, args []string) error {
+	return dispatch(a, "note", noteCommands, args)
+}
+
+func noteList(a *app, fs *flag.FlagSet, args []string) error {
+	projectRef := fs.String("project", "", "project ID or name")
+	taskID := fs.Int64("task", 0, "task ID")
+	output := addOutputFlags(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if (*projectRef == "") == (*taskID == 0) {
+		fs.Usage()
+		return errUsage
+	}
+	format, err := output.format()
+	if err != nil {
+		return err
+	}
+
+	var notes []models.Note
+	if *taskID != 0 {
+		notes, err = a.noteRepo.GetByTaskID(*taskID)
+		if err != nil {
+			return err
+		}
+	} else {
+		project, err := a.resolveProject(*projectRef)
+		if err != nil {
+			return err
+		}
+		notes, err = a.noteRepo.GetByProjectID(project.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if format != formatText {
+		return writeList(a, format, notes)
+	}
+
+	for _, note := range notes {
+		marker := "•"
+		if note.IsDescription {
+			marker = "Description:"
+		}
+		fmt.Fprintf(a.out, "#%d %s %s\n", note.ID, marker, note.Content)
+	}
+
+	return nil
+}
+
+func noteAdd(a *app, fs *flag.FlagSet, args []string) error {
+	projectRef := fs.String("project", "", "project ID or name")
+	taskID := fs.Int64("task", 0, "task ID")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	content := joinArgs(positional)
+	if content == "" || (*projectRef == "") == (*taskID == 0) {
+		fs.Usage()
+		return errUsage
+	}
+
+	var note *models.Note
+	if *taskID != 0 {
+		note, err = a.noteRepo.CreateForTask(*taskID, content)
+	} else {
+		var project *models.Project
+		project, err = a.resolveProject(*projectRef)
+		if err != nil {
+			return err
+		}
+		note, err = a.noteRepo.CreateForProject(project.ID, content)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "Created note #%d\n", note.ID)
+	return nil
+}
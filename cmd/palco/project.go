@@ -0,0 +1,244 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"palco/internal/database/models"
+	"palco/internal/dates"
+)
+
+var projectCommands = map[string]subcommand{
+	"list":      {"list [--archived | --all] [--json | --ndjson]", projectList},
+	"show":      {"show <id|name> [--json | --ndjson]", projectShow},
+	"add":       {"add <name> [--description text] [--due date]", projectAdd},
+	"archive":   {"archive <id|name>", projectArchive},
+	"unarchive": {"unarchive <id|name>", projectUnarchive},
+	"order":     {"order <id|name> (manual | priority)", projectOrder},
+}
+
+func runProject(a *app, args []string) error {
+	return dispatch(a, "project", projectCommands, args)
+}
+
+func projectList(a *app, fs *flag.FlagSet, args []string) error {
+	archived := fs.Bool("archived", false, "list archived projects only")
+	all := fs.Bool("all", false, "list active and archived projects")
+	output := addOutputFlags(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	format, err := output.format()
+	if err != nil {
+		return err
+	}
+
+	var projects []models.Project
+	switch {
+	case *all:
+		projects, err = a.projectRepo.GetAll()
+	case *archived:
+		projects, err = a.projectRepo.GetAllArchived()
+	default:
+		projects, err = a.projectRepo.GetAllActive()
+	}
+	if err != nil {
+		return err
+	}
+
+	if format != formatText {
+		return writeList(a, format, projects)
+	}
+
+	tw := tabwriter.NewWriter(a.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tDUE\tSTATUS")
+	for _, project := range projects {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", project.ID, project.Name, formatDueDate(project), projectStatus(project))
+	}
+	return tw.Flush()
+}
+
+func projectShow(a *app, fs *flag.FlagSet, args []string) error {
+	output := addOutputFlags(fs)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	format, err := output.format()
+	if err != nil {
+		return err
+	}
+
+	project, err := a.resolveProject(positional[0])
+	if err != nil {
+		return err
+	}
+
+	tasks, err := a.taskRepo.GetByProjectID(project.ID)
+	if err != nil {
+		return err
+	}
+	notes, err := a.noteRepo.GetByProjectID(project.ID)
+	if err != nil {
+		return err
+	}
+
+	if format != formatText {
+		trees, err := a.taskTrees(tasks)
+		if err != nil {
+			return err
+		}
+		return writeItem(a, format, models.ProjectTree{Project: *project, Notes: notes, Tasks: trees})
+	}
+
+	fmt.Fprintf(a.out, "#%d %s\n", project.ID, project.Name)
+	if project.Description.Valid && project.Description.String != "" {
+		fmt.Fprintf(a.out, "Description: %s\n", project.Description.String)
+	}
+	if project.DueDate.Valid {
+		fmt.Fprintf(a.out, "Due Date: %s\n", formatDueDate(*project))
+	}
+	fmt.Fprintf(a.out, "Status: %s\n", projectStatus(*project))
+	fmt.Fprintf(a.out, "Task order: %s\n", taskOrderName(*project))
+	fmt.Fprintf(a.out, "Created: %s\n", project.CreatedAt.Format("2006-01-02"))
+
+	completed := 0
+	for _, task := range tasks {
+		if task.Completed {
+			completed++
+		}
+	}
+	fmt.Fprintf(a.out, "Tasks: %d/%d completed\n", completed, len(tasks))
+
+	if len(notes) > 0 {
+		fmt.Fprintf(a.out, "Notes (%d):\n", len(notes))
+		for _, note := range notes {
+			fmt.Fprintf(a.out, "  • %s\n", note.Content)
+		}
+	}
+
+	return nil
+}
+
+func projectAdd(a *app, fs *flag.FlagSet, args []string) error {
+	description := fs.String("description", "", "project description")
+	due := fs.String("due", "", "due date, e.g. 2026-11-01, next friday, in 2 weeks or eom")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	name := joinArgs(positional)
+	if name == "" {
+		fs.Usage()
+		return errUsage
+	}
+
+	var dueDate *string
+	date, err := parseDate(*due)
+	if err != nil {
+		return err
+	}
+	if date != nil {
+		formatted := date.Format(dates.DateLayout)
+		dueDate = &formatted
+	}
+
+	project, err := a.projectRepo.Create(name, optional(*description), dueDate)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "Created project #%d %s\n", project.ID, project.Name)
+	return nil
+}
+
+func projectArchive(a *app, fs *flag.FlagSet, args []string) error {
+	return setProjectArchived(a, fs, args, true)
+}
+
+func projectUnarchive(a *app, fs *flag.FlagSet, args []string) error {
+	return setProjectArchived(a, fs, args, false)
+}
+
+func setProjectArchived(a *app, fs *flag.FlagSet, args []string, archived bool) error {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	project, err := a.resolveProject(positional[0])
+	if err != nil {
+		return err
+	}
+
+	verb := "Archived"
+	if archived {
+		err = a.projectRepo.Archive(project.ID)
+	} else {
+		verb = "Unarchived"
+		err = a.projectRepo.Unarchive(project.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "%s project #%d %s\n", verb, project.ID, project.Name)
+	return nil
+}
+
+// projectOrder switches a project between manual and priority task order
+func projectOrder(a *app, fs *flag.FlagSet, args []string) error {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 || (positional[1] != "manual" && positional[1] != "priority") {
+		fs.Usage()
+		return errUsage
+	}
+
+	project, err := a.resolveProject(positional[0])
+	if err != nil {
+		return err
+	}
+
+	project.ManualOrder = positional[1] == "manual"
+	if err := a.projectRepo.SetManualOrder(project.ID, project.ManualOrder); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "Project #%d %s lists its tasks in %s order\n", project.ID, project.Name, strings.ToLower(taskOrderName(*project)))
+	return nil
+}
+
+func formatDueDate(project models.Project) string {
+	if !project.DueDate.Valid {
+		return "-"
+	}
+	return project.DueDate.Time.Format("2006-01-02")
+}
+
+func projectStatus(project models.Project) string {
+	if project.Archived {
+		return "Archived"
+	}
+	return "Active"
+}
+
+func taskOrderName(project models.Project) string {
+	if project.ManualOrder {
+		return "Manual"
+	}
+	return "Priority"
+}
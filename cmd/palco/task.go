@@ -0,0 +1,605 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"palco/internal/database/models"
+	"palco/internal/recurrence"
+	"palco/internal/repository"
+)
+
+var taskCommands = map[string]subcommand{
+	"list":    {"list --project <id|name> [--pending] [--json | --ndjson]", taskList},
+	"show":    {"show <id> [--json | --ndjson]", taskShow},
+	"add":     {"add <title> [--project <id|name>] [--parent id] [--priority 0-4] [--description text] [--due date] [--start date] [--scheduled date] [--repeat rule]", taskAdd},
+	"done":    {"done <id>...", taskDone},
+	"undone":  {"undone <id>...", taskUndone},
+	"status":  {"status <id> <status>", taskStatus},
+	"block":   {"block <id> --by <id>[,<id>...]", taskBlock},
+	"unblock": {"unblock <id> --by <id>[,<id>...]", taskUnblock},
+	"move":    {"move <id> (--project <id|name> | --parent <id>)", taskMove},
+	"reorder": {"reorder <id> (--up n | --down n)", taskReorder},
+}
+
+func runTask(a *app, args []string) error {
+	return dispatch(a, "task", taskCommands, args)
+}
+
+func taskList(a *app, fs *flag.FlagSet, args []string) error {
+	projectRef := fs.String("project", "", "project ID or name")
+	pending := fs.Bool("pending", false, "hide completed tasks and their subtasks")
+	output := addOutputFlags(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *projectRef == "" {
+		fs.Usage()
+		return errUsage
+	}
+	format, err := output.format()
+	if err != nil {
+		return err
+	}
+
+	project, err := a.resolveProject(*projectRef)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := a.taskRepo.GetByProjectID(project.ID)
+	if err != nil {
+		return err
+	}
+
+	tasks, depths := models.OrganizeTasksHierarchically(tasks)
+	if *pending {
+		tasks, depths = pendingTasks(tasks, depths)
+	}
+
+	if format != formatText {
+		trees, err := a.taskTrees(tasks)
+		if err != nil {
+			return err
+		}
+		return writeList(a, format, trees)
+	}
+
+	workflow, err := a.statusRepo.GetWorkflow(project.ID)
+	if err != nil {
+		return err
+	}
+
+	for i, task := range tasks {
+		line := formatTaskLine(task)
+		if label := workflow.Label(task); label != "" {
+			line += " · " + label
+		}
+		fmt.Fprintf(a.out, "%s%s\n", strings.Repeat("  ", depths[i]), line)
+	}
+
+	return nil
+}
+
+// pendingTasks drops completed tasks and everything below them from a
+// hierarchically ordered task list
+func pendingTasks(tasks []models.Task, depths []int) ([]models.Task, []int) {
+	var keptTasks []models.Task
+	var keptDepths []int
+
+	skipBelow := -1
+	for i, task := range tasks {
+		if skipBelow >= 0 && depths[i] > skipBelow {
+			continue
+		}
+		skipBelow = -1
+
+		if task.Completed {
+			skipBelow = depths[i]
+			continue
+		}
+
+		keptTasks = append(keptTasks, task)
+		keptDepths = append(keptDepths, depths[i])
+	}
+
+	return keptTasks, keptDepths
+}
+
+func taskShow(a *app, fs *flag.FlagSet, args []string) error {
+	output := addOutputFlags(fs)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	format, err := output.format()
+	if err != nil {
+		return err
+	}
+
+	id, err := parseID("task", positional[0])
+	if err != nil {
+		return err
+	}
+
+	task, err := a.taskRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if format != formatText {
+		return a.writeTaskTree(format, *task)
+	}
+
+	subtasks, err := a.taskRepo.GetSubtasks(task.ID)
+	if err != nil {
+		return err
+	}
+	notes, err := a.noteRepo.GetByTaskID(task.ID)
+	if err != nil {
+		return err
+	}
+	blockers, err := a.dependencyRepo.GetBlockers(task.ID)
+	if err != nil {
+		return err
+	}
+	dependents, err := a.dependencyRepo.GetDependents(task.ID)
+	if err != nil {
+		return err
+	}
+	workflow, err := a.statusRepo.GetWorkflow(task.ProjectID.Int64)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(a.out, formatTaskLine(*task))
+	fmt.Fprintf(a.out, "Project: #%d\n", task.ProjectID.Int64)
+	if status := workflow.Of(*task); status.Name != "" {
+		fmt.Fprintf(a.out, "Status: %s\n", status.Name)
+	}
+	if task.ParentTaskID.Valid {
+		fmt.Fprintf(a.out, "Parent: #%d\n", task.ParentTaskID.Int64)
+	}
+	if task.DueAt.Valid {
+		fmt.Fprintf(a.out, "Due: %s\n", models.FormatTaskDate(task.DueAt.Time))
+	}
+	if task.StartAt.Valid {
+		fmt.Fprintf(a.out, "Start: %s\n", models.FormatTaskDate(task.StartAt.Time))
+	}
+	if task.ScheduledFor.Valid {
+		fmt.Fprintf(a.out, "Scheduled: %s\n", models.FormatTaskDate(task.ScheduledFor.Time))
+	}
+	if rule, err := recurrence.Parse(task.Recurrence.String); task.Recurrence.Valid && err == nil {
+		fmt.Fprintf(a.out, "Repeats: %s\n", rule.Describe())
+	}
+	fmt.Fprintf(a.out, "Created: %s\n", task.CreatedAt.Format("2006-01-02"))
+
+	for _, note := range notes {
+		if note.IsDescription {
+			fmt.Fprintf(a.out, "Description: %s\n", note.Content)
+		}
+	}
+
+	if len(subtasks) > 0 {
+		fmt.Fprintf(a.out, "Subtasks (%d):\n", len(subtasks))
+		for _, subtask := range subtasks {
+			fmt.Fprintf(a.out, "  %s\n", formatTaskLine(subtask))
+		}
+	}
+
+	if len(blockers) > 0 {
+		fmt.Fprintf(a.out, "Blocked by (%d):\n", len(blockers))
+		for _, blocker := range blockers {
+			fmt.Fprintf(a.out, "  %s\n", formatTaskLine(blocker))
+		}
+	}
+	if len(dependents) > 0 {
+		fmt.Fprintf(a.out, "Blocks (%d):\n", len(dependents))
+		for _, dependent := range dependents {
+			fmt.Fprintf(a.out, "  %s\n", formatTaskLine(dependent))
+		}
+	}
+
+	var otherNotes []models.Note
+	for _, note := range notes {
+		if !note.IsDescription {
+			otherNotes = append(otherNotes, note)
+		}
+	}
+	if len(otherNotes) > 0 {
+		fmt.Fprintf(a.out, "Notes (%d):\n", len(otherNotes))
+		for _, note := range otherNotes {
+			fmt.Fprintf(a.out, "  • %s\n", note.Content)
+		}
+	}
+
+	return nil
+}
+
+func taskAdd(a *app, fs *flag.FlagSet, args []string) error {
+	projectRef := fs.String("project", "", "project ID or name (defaults to the parent's project)")
+	parent := fs.Int64("parent", 0, "parent task ID, creates a subtask")
+	priorityStr := fs.String("priority", "0", "priority (0=None, 1=Low, 2=Medium, 3=High, 4=Urgent)")
+	description := fs.String("description", "", "task description")
+	due := fs.String("due", "", "due date, e.g. 2026-11-01, friday, tomorrow 3pm or in 3 days")
+	start := fs.String("start", "", "date work on the task can start")
+	scheduled := fs.String("scheduled", "", "day the task is planned for")
+	repeatText := fs.String("repeat", "", "repeat rule, e.g. daily, weekly on mon, wed, monthly on the 15th or every 3 days after completion")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	title := joinArgs(positional)
+	if title == "" || (*projectRef == "" && *parent == 0) {
+		fs.Usage()
+		return errUsage
+	}
+
+	priority, err := parsePriority(*priorityStr)
+	if err != nil {
+		return err
+	}
+
+	var dates models.TaskDates
+	for _, flag := range []struct {
+		name   string
+		value  string
+		target **time.Time
+	}{
+		{"due", *due, &dates.DueAt},
+		{"start", *start, &dates.StartAt},
+		{"scheduled", *scheduled, &dates.ScheduledFor},
+	} {
+		t, err := parseDate(flag.value)
+		if err != nil {
+			return fmt.Errorf("--%s: %w", flag.name, err)
+		}
+		*flag.target = t
+	}
+
+	repeat, err := parseRepeat(*repeatText)
+	if err != nil {
+		return fmt.Errorf("--repeat: %w", err)
+	}
+
+	var parentTaskID *int64
+	var projectID int64
+	if *parent != 0 {
+		parentTask, err := a.taskRepo.GetByID(*parent)
+		if err != nil {
+			return err
+		}
+		parentTaskID = &parentTask.ID
+		projectID = parentTask.ProjectID.Int64
+	}
+
+	if *projectRef != "" {
+		project, err := a.resolveProject(*projectRef)
+		if err != nil {
+			return err
+		}
+		if parentTaskID != nil && project.ID != projectID {
+			return fmt.Errorf("parent task #%d does not belong to project %q", *parentTaskID, project.Name)
+		}
+		projectID = project.ID
+	}
+
+	project, err := a.projectRepo.GetByID(projectID)
+	if err != nil {
+		return err
+	}
+	if project.Archived {
+		return fmt.Errorf("project %q is archived, unarchive it first", project.Name)
+	}
+
+	task, err := a.taskRepo.Create(projectID, parentTaskID, title, optional(*description), priority, dates, repeat)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "Created task #%d %s\n", task.ID, task.Title)
+	return nil
+}
+
+func taskDone(a *app, fs *flag.FlagSet, args []string) error {
+	return setTasksCompleted(a, fs, args, true)
+}
+
+func taskUndone(a *app, fs *flag.FlagSet, args []string) error {
+	return setTasksCompleted(a, fs, args, false)
+}
+
+func setTasksCompleted(a *app, fs *flag.FlagSet, args []string, completed bool) error {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	for _, arg := range positional {
+		id, err := parseID("task", arg)
+		if err != nil {
+			return err
+		}
+
+		completion, err := a.taskRepo.SetCompleted(id, completed, time.Now())
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(a.out, formatTaskLine(*completion.Task))
+		a.printCompletion(completion)
+	}
+
+	return nil
+}
+
+// taskStatus moves a task to another status of its project's workflow
+func taskStatus(a *app, fs *flag.FlagSet, args []string) error {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 2 {
+		fs.Usage()
+		return errUsage
+	}
+
+	id, err := parseID("task", positional[0])
+	if err != nil {
+		return err
+	}
+
+	task, err := a.taskRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	workflow, err := a.statusRepo.GetWorkflow(task.ProjectID.Int64)
+	if err != nil {
+		return err
+	}
+
+	name := joinArgs(positional[1:])
+	status, ok := workflow.ByName(name)
+	if !ok {
+		var names []string
+		for _, s := range workflow {
+			names = append(names, s.Name)
+		}
+		return fmt.Errorf("status %q is not in the workflow (%s)", name, strings.Join(names, ", "))
+	}
+
+	completion, err := a.taskRepo.SetStatus(task.ID, status.ID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "%s · %s\n", formatTaskLine(*completion.Task), status.Name)
+	a.printCompletion(completion)
+	return nil
+}
+
+// printCompletion lists the next occurrence a completion created and the
+// tasks it no longer blocks
+func (a *app) printCompletion(completion *repository.Completion) {
+	if completion.Next != nil {
+		fmt.Fprintf(a.out, "Next: %s\n", formatTaskLine(*completion.Next))
+	}
+	for _, t := range completion.Unblocked {
+		fmt.Fprintf(a.out, "Unblocked: %s\n", formatTaskLine(t))
+	}
+}
+
+func taskBlock(a *app, fs *flag.FlagSet, args []string) error {
+	return linkTasks(a, fs, args, true)
+}
+
+func taskUnblock(a *app, fs *flag.FlagSet, args []string) error {
+	return linkTasks(a, fs, args, false)
+}
+
+// linkTasks adds or removes the dependencies of a task on the --by tasks
+func linkTasks(a *app, fs *flag.FlagSet, args []string, block bool) error {
+	by := fs.String("by", "", "comma separated IDs of the tasks it waits on")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || *by == "" {
+		fs.Usage()
+		return errUsage
+	}
+
+	id, err := parseID("task", positional[0])
+	if err != nil {
+		return err
+	}
+
+	var blockers []string
+	for _, ref := range strings.Split(*by, ",") {
+		ref = strings.TrimSpace(ref)
+		blockerID, err := parseID("task", ref)
+		if err != nil {
+			return err
+		}
+
+		if block {
+			err = a.dependencyRepo.Add(id, blockerID)
+		} else {
+			err = a.dependencyRepo.Remove(id, blockerID)
+		}
+		if err != nil {
+			return err
+		}
+		blockers = append(blockers, ref)
+	}
+
+	verb := "now blocked by"
+	if !block {
+		verb = "no longer blocked by"
+	}
+	fmt.Fprintf(a.out, "Task #%d is %s #%s\n", id, verb, strings.Join(blockers, ", #"))
+	return nil
+}
+
+// taskMove moves a task with its subtasks under another task, or to the
+// top level of a project
+func taskMove(a *app, fs *flag.FlagSet, args []string) error {
+	projectRef := fs.String("project", "", "project ID or name, moves the task to its top level")
+	parent := fs.Int64("parent", 0, "new parent task ID")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || (*projectRef == "") == (*parent == 0) {
+		fs.Usage()
+		return errUsage
+	}
+
+	id, err := parseID("task", positional[0])
+	if err != nil {
+		return err
+	}
+
+	var parentTaskID *int64
+	var projectID int64
+	if *parent != 0 {
+		parentTask, err := a.taskRepo.GetByID(*parent)
+		if err != nil {
+			return err
+		}
+		parentTaskID = &parentTask.ID
+		projectID = parentTask.ProjectID.Int64
+	} else {
+		project, err := a.resolveProject(*projectRef)
+		if err != nil {
+			return err
+		}
+		projectID = project.ID
+	}
+
+	project, err := a.projectRepo.GetByID(projectID)
+	if err != nil {
+		return err
+	}
+	if project.Archived {
+		return fmt.Errorf("project %q is archived, unarchive it first", project.Name)
+	}
+
+	task, err := a.taskRepo.Move(id, projectID, parentTaskID)
+	if err != nil {
+		return err
+	}
+
+	if parentTaskID != nil {
+		fmt.Fprintf(a.out, "Moved task #%d %s under #%d\n", task.ID, task.Title, *parentTaskID)
+	} else {
+		fmt.Fprintf(a.out, "Moved task #%d %s to %s\n", task.ID, task.Title, project.Name)
+	}
+	return nil
+}
+
+// taskReorder moves a task up or down among its siblings in a project
+// ordered by hand
+func taskReorder(a *app, fs *flag.FlagSet, args []string) error {
+	up := fs.Int("up", 0, "places to move the task up")
+	down := fs.Int("down", 0, "places to move the task down")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || (*up == 0) == (*down == 0) || *up < 0 || *down < 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	id, err := parseID("task", positional[0])
+	if err != nil {
+		return err
+	}
+
+	task, err := a.taskRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	project, err := a.projectRepo.GetByID(task.ProjectID.Int64)
+	if err != nil {
+		return err
+	}
+	if !project.ManualOrder {
+		return fmt.Errorf("project %q lists its tasks by priority, switch it with palco project order %d manual", project.Name, project.ID)
+	}
+
+	if _, err := a.taskRepo.Reorder(id, *down-*up); err != nil {
+		return err
+	}
+
+	siblings, err := a.taskRepo.GetByProjectID(project.ID)
+	if err != nil {
+		return err
+	}
+	for _, sibling := range siblings {
+		if sibling.ParentTaskID == task.ParentTaskID {
+			fmt.Fprintln(a.out, formatTaskLine(sibling))
+		}
+	}
+	return nil
+}
+
+// writeTaskTree prints task with all of its descendants and their notes
+func (a *app) writeTaskTree(format int, task models.Task) error {
+	project, err := a.taskRepo.GetByProjectID(task.ProjectID.Int64)
+	if err != nil {
+		return err
+	}
+
+	// Keep only the task and its descendants
+	tasks, depths := models.OrganizeTasksHierarchically(project)
+	var subtree []models.Task
+	for i, t := range tasks {
+		if t.ID == task.ID {
+			subtree = append(subtree, t)
+			for j := i + 1; j < len(tasks) && depths[j] > depths[i]; j++ {
+				subtree = append(subtree, tasks[j])
+			}
+			break
+		}
+	}
+
+	trees, err := a.taskTrees(subtree)
+	if err != nil {
+		return err
+	}
+	if len(trees) == 0 {
+		return fmt.Errorf("task #%d not found", task.ID)
+	}
+
+	return writeItem(a, format, trees[0])
+}
+
+// formatTaskLine renders a task as "#id [✓] title (Priority) due YYYY-MM-DD"
+func formatTaskLine(task models.Task) string {
+	status := "[ ]"
+	if task.Completed {
+		status = "[✓]"
+	}
+
+	line := fmt.Sprintf("#%d %s %s", task.ID, status, task.Title)
+	if task.Priority != models.PriorityNone {
+		line += fmt.Sprintf(" (%s)", models.PriorityText(task.Priority))
+	}
+	if task.DueAt.Valid {
+		line += " due " + models.FormatTaskDate(task.DueAt.Time)
+	}
+	return line
+}
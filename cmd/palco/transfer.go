@@ -0,0 +1,437 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"palco/internal/database/models"
+	"palco/internal/markdown"
+	"palco/internal/repository"
+	"palco/internal/taskcsv"
+	"palco/internal/taskwarrior"
+	"palco/internal/todotxt"
+)
+
+func runExport(a *app, args []string) error {
+	fs := newFlagSet("export", "[--format json|markdown|todotxt|csv] [--project <id|name>] [--columns list] [--output file]")
+	format := fs.String("format", "json", "output format: json (whole workspace), markdown (one project), todotxt or csv")
+	projectRef := fs.String("project", "", "project to export (required for markdown, todotxt and csv default to all active projects)")
+	columns := fs.String("columns", "", "csv: comma separated columns (default: "+strings.Join(taskcsv.Columns, ",")+")")
+	output := fs.String("output", "", "write to this file instead of stdout")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	switch *format {
+	case "json":
+		if *projectRef != "" {
+			return fmt.Errorf("--project is not supported with --format json")
+		}
+		return exportWorkspace(a, *output)
+	case "markdown", "md":
+		if *projectRef == "" {
+			fs.Usage()
+			return errUsage
+		}
+		return exportMarkdown(a, *projectRef, *output)
+	case "todotxt":
+		return exportTodoTxt(a, *projectRef, *output)
+	case "csv":
+		return exportCSV(a, *projectRef, *columns, *output)
+	default:
+		return fmt.Errorf("unknown export format %q", *format)
+	}
+}
+
+func exportWorkspace(a *app, output string) error {
+	ws, err := a.workspaceRepo.Export()
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(a, output, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(ws); err != nil {
+			return fmt.Errorf("failed to encode workspace: %w", err)
+		}
+		return nil
+	})
+}
+
+func exportMarkdown(a *app, projectRef, output string) error {
+	tree, err := a.projectTree(projectRef)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(a, output, func(w io.Writer) error {
+		return markdown.Render(w, *tree)
+	})
+}
+
+func exportTodoTxt(a *app, projectRef, output string) error {
+	projects, tasks, err := a.exportTasks(projectRef)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(a, output, func(w io.Writer) error {
+		return todotxt.Write(w, projects, tasks)
+	})
+}
+
+func exportCSV(a *app, projectRef, columnSpec, output string) error {
+	columns, err := taskcsv.ParseColumns(columnSpec)
+	if err != nil {
+		return err
+	}
+
+	projects, tasks, err := a.exportTasks(projectRef)
+	if err != nil {
+		return err
+	}
+
+	names := make(map[int64]string, len(projects))
+	for _, project := range projects {
+		names[project.ID] = project.Name
+	}
+
+	rows := make([]taskcsv.Row, len(tasks))
+	for i, task := range tasks {
+		rows[i] = taskcsv.Row{Task: task, Project: names[task.ProjectID.Int64]}
+		if slices.Contains(columns, "description") {
+			note, err := a.noteRepo.GetTaskDescription(task.ID)
+			if err != nil {
+				return err
+			}
+			if note != nil {
+				rows[i].Description = note.Content
+			}
+		}
+	}
+
+	return writeOutput(a, output, func(w io.Writer) error {
+		return taskcsv.Write(w, columns, rows)
+	})
+}
+
+// exportTasks returns the given project, or all active projects, with
+// their tasks in hierarchical order
+func (a *app) exportTasks(projectRef string) ([]models.Project, []models.Task, error) {
+	var projects []models.Project
+	if projectRef != "" {
+		project, err := a.resolveProject(projectRef)
+		if err != nil {
+			return nil, nil, err
+		}
+		projects = []models.Project{*project}
+	} else {
+		var err error
+		projects, err = a.projectRepo.GetAllActive()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var tasks []models.Task
+	for _, project := range projects {
+		projectTasks, err := a.taskRepo.GetByProjectID(project.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		projectTasks, _ = models.OrganizeTasksHierarchically(projectTasks)
+		tasks = append(tasks, projectTasks...)
+	}
+
+	return projects, tasks, nil
+}
+
+func runImport(a *app, args []string) error {
+	fs := newFlagSet("import", "[--format json|markdown|todotxt|taskwarrior|csv] [--project <id|name>] [--split-projects] [file]")
+	format := fs.String("format", "", "input format: json, markdown, todotxt, taskwarrior or csv (default: from the file extension, else json)")
+	projectRef := fs.String("project", "", "markdown: add tasks to this project, creating it when no project has this name; todotxt, taskwarrior, csv: project for tasks without one")
+	splitProjects := fs.Bool("split-projects", false, "taskwarrior: import \"home.garden\" as project \"home\" with a \"garden\" task instead of a \"home.garden\" project")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	if *format == "" {
+		*format = formatFromExtension(positional, "json")
+	}
+
+	taskwarriorOpts := taskwarrior.Options{DefaultProject: *projectRef, SplitProjects: *splitProjects}
+	if taskwarriorOpts.DefaultProject == "" {
+		taskwarriorOpts.DefaultProject = "Inbox"
+	}
+
+	var result *repository.ImportResult
+	switch *format {
+	case "json":
+		result, err = importJSON(a, positional, *projectRef, taskwarriorOpts)
+	case "markdown", "md":
+		result, err = importMarkdown(a, positional, *projectRef)
+	case "todotxt":
+		result, err = importTodoTxt(a, positional, *projectRef)
+	case "taskwarrior":
+		result, err = importTaskwarrior(a, positional, taskwarriorOpts)
+	case "csv":
+		result, err = importCSV(a, positional, *projectRef)
+	default:
+		return fmt.Errorf("unknown import format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "Imported %d projects, %d tasks and %d notes\n", result.Projects, result.Tasks, result.Notes)
+	return nil
+}
+
+// importJSON imports a palco workspace, or Taskwarrior's export when the
+// document is an array
+func importJSON(a *app, positional []string, projectRef string, taskwarriorOpts taskwarrior.Options) (*repository.ImportResult, error) {
+	var data []byte
+	err := readInput(positional, func(r io.Reader) error {
+		var err error
+		data, err = io.ReadAll(r)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		return importTaskwarriorFrom(a, bytes.NewReader(data), taskwarriorOpts)
+	}
+
+	if projectRef != "" {
+		return nil, fmt.Errorf("--project is not supported with --format json")
+	}
+
+	var ws models.Workspace
+	if err := json.Unmarshal(data, &ws); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace: %w", err)
+	}
+
+	return a.workspaceRepo.Import(&ws, repository.ImportOptions{})
+}
+
+func importMarkdown(a *app, positional []string, projectRef string) (*repository.ImportResult, error) {
+	var ws *models.Workspace
+	err := readInput(positional, func(r io.Reader) error {
+		var err error
+		ws, err = markdown.Parse(r)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := a.targetProject(ws, markdown.ProjectID, projectRef, positional)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.workspaceRepo.Import(ws, opts)
+}
+
+func importTodoTxt(a *app, positional []string, defaultProject string) (*repository.ImportResult, error) {
+	if defaultProject == "" {
+		defaultProject = "Inbox"
+	}
+
+	var ws *models.Workspace
+	err := readInput(positional, func(r io.Reader) error {
+		var err error
+		ws, err = todotxt.Parse(r, defaultProject)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Tags match existing projects by name, spaces written as "-"
+	opts, err := a.matchExistingProjects(ws, todotxt.ProjectTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.workspaceRepo.Import(ws, opts)
+}
+
+// importTaskwarrior imports "task export" output
+func importTaskwarrior(a *app, positional []string, opts taskwarrior.Options) (*repository.ImportResult, error) {
+	var result *repository.ImportResult
+	err := readInput(positional, func(r io.Reader) error {
+		var err error
+		result, err = importTaskwarriorFrom(a, r, opts)
+		return err
+	})
+	return result, err
+}
+
+func importTaskwarriorFrom(a *app, r io.Reader, opts taskwarrior.Options) (*repository.ImportResult, error) {
+	ws, err := taskwarrior.Parse(r, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	importOpts, err := a.matchExistingProjects(ws, strings.TrimSpace)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.workspaceRepo.Import(ws, importOpts)
+}
+
+func importCSV(a *app, positional []string, defaultProject string) (*repository.ImportResult, error) {
+	if defaultProject == "" {
+		defaultProject = "Inbox"
+	}
+
+	var ws *models.Workspace
+	err := readInput(positional, func(r io.Reader) error {
+		var err error
+		ws, err = taskcsv.Parse(r, defaultProject)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := a.matchExistingProjects(ws, strings.TrimSpace)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.workspaceRepo.Import(ws, opts)
+}
+
+// matchExistingProjects maps the document's projects onto existing projects
+// whose names are equal, ignoring case, after applying normalize to both
+func (a *app) matchExistingProjects(ws *models.Workspace, normalize func(string) string) (repository.ImportOptions, error) {
+	existing, err := a.projectRepo.GetAll()
+	if err != nil {
+		return repository.ImportOptions{}, err
+	}
+
+	opts := repository.ImportOptions{ExistingProjects: make(map[int64]int64)}
+	for _, docProject := range ws.Projects {
+		for _, project := range existing {
+			if strings.EqualFold(normalize(project.Name), normalize(docProject.Name)) {
+				opts.ExistingProjects[docProject.ID] = project.ID
+				break
+			}
+		}
+	}
+
+	return opts, nil
+}
+
+// targetProject decides where the single project parsed from a document
+// goes. With a project reference that matches an existing project, the
+// tasks are merged into it; otherwise a new project is created, named
+// after the reference, the document itself or the input file.
+func (a *app) targetProject(ws *models.Workspace, docProjectID int64, projectRef string, positional []string) (repository.ImportOptions, error) {
+	var project *models.Project
+	for i := range ws.Projects {
+		if ws.Projects[i].ID == docProjectID {
+			project = &ws.Projects[i]
+		}
+	}
+	if project == nil {
+		return repository.ImportOptions{}, fmt.Errorf("document has no project %d", docProjectID)
+	}
+
+	if projectRef != "" {
+		existing, err := a.lookupProject(projectRef)
+		if err != nil {
+			return repository.ImportOptions{}, err
+		}
+		if existing != nil {
+			return repository.ImportOptions{ExistingProjects: map[int64]int64{docProjectID: existing.ID}}, nil
+		}
+		if _, numeric := strconv.ParseInt(projectRef, 10, 64); numeric == nil {
+			return repository.ImportOptions{}, fmt.Errorf("project %q not found", projectRef)
+		}
+		project.Name = projectRef
+	}
+
+	if project.Name == "" && len(positional) > 0 && positional[0] != "-" {
+		base := filepath.Base(positional[0])
+		project.Name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	if project.Name == "" {
+		project.Name = "Imported"
+	}
+
+	return repository.ImportOptions{}, nil
+}
+
+// formatFromExtension guesses the format of the input file
+func formatFromExtension(positional []string, fallback string) string {
+	if len(positional) == 0 {
+		return fallback
+	}
+
+	switch strings.ToLower(filepath.Ext(positional[0])) {
+	case ".md", ".markdown":
+		return "markdown"
+	case ".json":
+		return "json"
+	case ".txt":
+		return "todotxt"
+	case ".csv":
+		return "csv"
+	default:
+		return fallback
+	}
+}
+
+// writeOutput calls write with the file at path, or with stdout when path
+// is empty or "-"
+func writeOutput(a *app, path string, write func(w io.Writer) error) error {
+	if path == "" || path == "-" {
+		return write(a.out)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readInput calls read with the file named by the single positional
+// argument, or with stdin when there is none or it is "-"
+func readInput(positional []string, read func(r io.Reader) error) error {
+	if len(positional) == 0 || positional[0] == "-" {
+		return read(os.Stdin)
+	}
+
+	f, err := os.Open(positional[0])
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", positional[0], err)
+	}
+	defer f.Close()
+
+	return read(f)
+}
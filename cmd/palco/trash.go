@@ -0,0 +1,164 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"palco/internal/database/models"
+)
+
+var trashCommands = map[string]subcommand{
+	"list":    {"list [--json | --ndjson]", trashList},
+	"restore": {"restore <project|task|note> <id>", trashRestore},
+	"delete":  {"delete <project|task|note> <id>  (permanently)", trashDelete},
+	"purge":   {"purge (--older-than <age> | --all)", trashPurge},
+}
+
+func runTrash(a *app, args []string) error {
+	return dispatch(a, "trash", trashCommands, args)
+}
+
+func trashList(a *app, fs *flag.FlagSet, args []string) error {
+	output := addOutputFlags(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	format, err := output.format()
+	if err != nil {
+		return err
+	}
+
+	items, err := a.trashRepo.GetAll()
+	if err != nil {
+		return err
+	}
+
+	if format != formatText {
+		return writeList(a, format, items)
+	}
+
+	if len(items) == 0 {
+		fmt.Fprintln(a.out, "The trash is empty")
+		return nil
+	}
+
+	fmt.Fprintf(a.out, "%-8s %-5s %-17s %s\n", "KIND", "ID", "DELETED", "TITLE")
+	for _, item := range items {
+		title, _, _ := strings.Cut(item.Title, "\n")
+		if item.Project != "" && item.Kind != models.TrashProject {
+			title += " (" + item.Project + ")"
+		}
+		if item.Tasks > 0 || item.Notes > 0 {
+			title += fmt.Sprintf(" +%d tasks, %d notes", item.Tasks, item.Notes)
+		}
+		fmt.Fprintf(a.out, "%-8s %-5d %-17s %s\n", item.Kind, item.ID, item.DeletedAt.Local().Format("2006-01-02 15:04"), title)
+	}
+
+	return nil
+}
+
+func trashRestore(a *app, fs *flag.FlagSet, args []string) error {
+	kind, id, err := parseTrashItem(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if err := a.trashRepo.Restore(kind, id); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "Restored %s #%d\n", kind, id)
+	return nil
+}
+
+func trashDelete(a *app, fs *flag.FlagSet, args []string) error {
+	kind, id, err := parseTrashItem(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if err := a.trashRepo.Delete(kind, id); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "Permanently deleted %s #%d\n", kind, id)
+	return nil
+}
+
+func trashPurge(a *app, fs *flag.FlagSet, args []string) error {
+	olderThan := fs.String("older-than", "", "only purge items deleted longer ago than this, e.g. 30d, 2w or 12h")
+	all := fs.Bool("all", false, "purge everything in the trash")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 || (*olderThan == "") == !*all {
+		fs.Usage()
+		return errUsage
+	}
+
+	before := time.Now()
+	if *olderThan != "" {
+		age, err := parseAge(*olderThan)
+		if err != nil {
+			return err
+		}
+		before = before.Add(-age)
+	}
+
+	result, err := a.trashRepo.Purge(before)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "Purged %d projects, %d tasks and %d notes\n", result.Projects, result.Tasks, result.Notes)
+	return nil
+}
+
+// parseTrashItem parses the "<kind> <id>" arguments of restore and delete
+func parseTrashItem(fs *flag.FlagSet, args []string) (string, int64, error) {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(positional) != 2 {
+		fs.Usage()
+		return "", 0, errUsage
+	}
+
+	kind := positional[0]
+	switch kind {
+	case models.TrashProject, models.TrashTask, models.TrashNote:
+	default:
+		return "", 0, fmt.Errorf("unknown kind %q (expected project, task or note)", kind)
+	}
+
+	id, err := parseID(kind, positional[1])
+	if err != nil {
+		return "", 0, err
+	}
+	return kind, id, nil
+}
+
+// parseAge parses a duration that may also be given in days ("30d") or
+// weeks ("2w")
+func parseAge(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid age %q", s)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+
+	age, err := time.ParseDuration(s)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid age %q (expected e.g. 30d, 2w or 12h)", s)
+	}
+	return age, nil
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"palco/internal/database/models"
+)
+
+var workflowCommands = map[string]subcommand{
+	"show": {"show <id|name>", workflowShow},
+	"set":  {"set <id|name> <status>,<status>... [--default name] [--done name,...] [--allow from>to,...]", workflowSet},
+}
+
+func runWorkflow(a *app, args []string) error {
+	return dispatch(a, "workflow", workflowCommands, args)
+}
+
+// workflowShow lists a project's statuses with how many tasks are in each
+func workflowShow(a *app, fs *flag.FlagSet, args []string) error {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	project, err := a.resolveProject(positional[0])
+	if err != nil {
+		return err
+	}
+
+	workflow, err := a.statusRepo.GetWorkflow(project.ID)
+	if err != nil {
+		return err
+	}
+	tasks, err := a.taskRepo.GetByProjectID(project.ID)
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[int64]int, len(workflow))
+	for _, task := range tasks {
+		counts[workflow.Of(task).ID]++
+	}
+
+	fmt.Fprintf(a.out, "#%d %s\n", project.ID, project.Name)
+	for _, status := range workflow {
+		fmt.Fprintf(a.out, "  %s\n", formatStatusLine(workflow, status, counts[status.ID]))
+	}
+	return nil
+}
+
+// workflowSet replaces a project's statuses. Without flags the first status
+// is the default one and the last the done one, and every move is allowed.
+func workflowSet(a *app, fs *flag.FlagSet, args []string) error {
+	defaultName := fs.String("default", "", "status new and reopened tasks start in (default the first)")
+	done := fs.String("done", "", "comma separated statuses that complete a task (default the last)")
+	allow := fs.String("allow", "", "comma separated from>to moves; statuses left out allow every move")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 2 {
+		fs.Usage()
+		return errUsage
+	}
+
+	project, err := a.resolveProject(positional[0])
+	if err != nil {
+		return err
+	}
+
+	var workflow models.Workflow
+	for _, name := range splitList(joinArgs(positional[1:])) {
+		workflow = append(workflow, models.Status{Name: name})
+	}
+	if len(workflow) == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	if err := markStatuses(workflow, *defaultName, workflow[0].Name, func(s *models.Status) { s.Default = true }); err != nil {
+		return err
+	}
+	if err := markStatuses(workflow, *done, workflow[len(workflow)-1].Name, func(s *models.Status) { s.Done = true }); err != nil {
+		return err
+	}
+
+	transitions := make(map[string][]string)
+	for _, move := range splitList(*allow) {
+		from, to, ok := strings.Cut(move, ">")
+		if !ok {
+			return fmt.Errorf("invalid move %q, expected from>to", move)
+		}
+		from = strings.TrimSpace(from)
+		transitions[from] = append(transitions[from], strings.TrimSpace(to))
+	}
+
+	workflow, err = a.statusRepo.SetWorkflow(project.ID, workflow, transitions)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "Updated the workflow of project #%d %s\n", project.ID, project.Name)
+	for _, status := range workflow {
+		fmt.Fprintf(a.out, "  %s\n", formatStatusLine(workflow, status, -1))
+	}
+	return nil
+}
+
+// markStatuses applies mark to the comma separated statuses in names, or to
+// fallback when names is empty
+func markStatuses(workflow models.Workflow, names, fallback string, mark func(*models.Status)) error {
+	if names == "" {
+		names = fallback
+	}
+	for _, name := range splitList(names) {
+		found := false
+		for i := range workflow {
+			if strings.EqualFold(workflow[i].Name, name) {
+				mark(&workflow[i])
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("status %q is not in the workflow", name)
+		}
+	}
+	return nil
+}
+
+// splitList splits a comma separated list, dropping empty entries
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// formatStatusLine renders a status as "Todo (default, 3 tasks) → In
+// Progress, Blocked". count is left out when negative.
+func formatStatusLine(workflow models.Workflow, status models.Status, count int) string {
+	var tags []string
+	if status.Default {
+		tags = append(tags, "default")
+	}
+	if status.Done {
+		tags = append(tags, "done")
+	}
+	if count >= 0 {
+		noun := "tasks"
+		if count == 1 {
+			noun = "task"
+		}
+		tags = append(tags, fmt.Sprintf("%d %s", count, noun))
+	}
+
+	line := status.Name
+	if len(tags) > 0 {
+		line += " (" + strings.Join(tags, ", ") + ")"
+	}
+
+	var next []string
+	for _, id := range status.Next {
+		if target, ok := workflow.Find(id); ok {
+			next = append(next, target.Name)
+		}
+	}
+	if len(next) > 0 {
+		line += " → " + strings.Join(next, ", ")
+	}
+	return line
+}
@@ -0,0 +1,121 @@
+// Package capture parses quick-capture text such as
+// "Call the plumber +Home !3 @due:friday" into a title and the project,
+// priority and due date given by its inline tokens.
+package capture
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"palco/internal/database/models"
+	"palco/internal/dates"
+)
+
+var (
+	projectToken  = regexp.MustCompile(`^\+(\S+)$`)
+	priorityToken = regexp.MustCompile(`^!([0-4])$`)
+	dueToken      = regexp.MustCompile(`^@due:(\S+)$`)
+	quotedDue     = regexp.MustCompile(`(?:^|\s)@due:"([^"]*)"`)
+)
+
+// Entry is a parsed capture. Only the first line carries tokens; the
+// remaining lines are kept as the body.
+type Entry struct {
+	Title    string
+	Body     string
+	Project  string
+	Priority int
+	Due      *time.Time
+}
+
+// Parse extracts the inline tokens from the first line of text. Relative
+// due dates are resolved against now; a date of several words is quoted,
+// @due:"tomorrow 3pm", or joined with dashes, @due:tomorrow-3pm. Tokens that
+// look like tokens but cannot be understood, such as "@due:someday", are an
+// error so nothing is filed with the wrong date.
+func Parse(text string, now time.Time) (Entry, error) {
+	first, body, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	entry := Entry{Body: strings.TrimSpace(body), Priority: models.PriorityNone}
+
+	if m := quotedDue.FindStringSubmatchIndex(first); m != nil {
+		due, err := dates.Parse(first[m[2]:m[3]], now, now.Location())
+		if err != nil {
+			return Entry{}, err
+		}
+		entry.Due = &due
+		first = first[:m[0]] + " " + first[m[1]:]
+	}
+
+	var words []string
+	for _, word := range strings.Fields(first) {
+		if m := projectToken.FindStringSubmatch(word); m != nil && entry.Project == "" {
+			entry.Project = m[1]
+			continue
+		}
+		if m := priorityToken.FindStringSubmatch(word); m != nil {
+			entry.Priority = int(m[1][0] - '0')
+			continue
+		}
+		if m := dueToken.FindStringSubmatch(word); m != nil {
+			due, err := parseDue(m[1], now)
+			if err != nil {
+				return Entry{}, err
+			}
+			entry.Due = &due
+			continue
+		}
+		words = append(words, word)
+	}
+
+	entry.Title = strings.Join(words, " ")
+	if entry.Title == "" {
+		return Entry{}, fmt.Errorf("nothing to capture")
+	}
+
+	return entry, nil
+}
+
+// parseDue parses a one-word due date, reading dashes as spaces when the
+// word is not a date as written, so "next-friday-3pm" works like
+// "next friday 3pm" while "2026-11-01" stays a date
+func parseDue(value string, now time.Time) (time.Time, error) {
+	due, err := dates.Parse(value, now, now.Location())
+	if err != nil && strings.Contains(value, "-") {
+		if spaced, spacedErr := dates.Parse(strings.ReplaceAll(value, "-", " "), now, now.Location()); spacedErr == nil {
+			return spaced, nil
+		}
+	}
+	return due, err
+}
+
+// String formats the entry back into capture text, with the due date
+// resolved so it keeps its meaning when read later
+func (e Entry) String() string {
+	parts := []string{e.Title}
+	if e.Project != "" {
+		parts = append(parts, "+"+e.Project)
+	}
+	if e.Priority != models.PriorityNone {
+		parts = append(parts, fmt.Sprintf("!%d", e.Priority))
+	}
+	if e.Due != nil {
+		parts = append(parts, "@due:"+formatDue(*e.Due))
+	}
+
+	text := strings.Join(parts, " ")
+	if e.Body != "" {
+		text += "\n" + e.Body
+	}
+	return text
+}
+
+// formatDue writes a due date as a token value, with the time of day when
+// it is not midnight, e.g. "2026-11-01" or "2026-11-01T15:00"
+func formatDue(due time.Time) string {
+	if due.Hour() == 0 && due.Minute() == 0 {
+		return due.Format(dates.DateLayout)
+	}
+	return due.Format(dates.DateLayout + "T15:04")
+}
@@ -0,0 +1,75 @@
+package capture
+
+import (
+	"testing"
+	"time"
+
+	"palco/internal/database/models"
+)
+
+// now is Wednesday 2026-10-14, 09:00
+var now = time.Date(2026, time.October, 14, 9, 0, 0, 0, time.UTC)
+
+func at(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		title    string
+		project  string
+		priority int
+		due      time.Time
+		body     string
+	}{
+		{"Call the plumber", "Call the plumber", "", models.PriorityNone, time.Time{}, ""},
+		{"Call the plumber +Home !3 @due:friday", "Call the plumber", "Home", models.PriorityHigh, at(2026, time.October, 16, 0, 0), ""},
+		{"+Home !0 Call the plumber", "Call the plumber", "Home", models.PriorityNone, time.Time{}, ""},
+		{"Call +Home +Work", "Call +Work", "Home", models.PriorityNone, time.Time{}, ""},
+		{"Call the plumber !5", "Call the plumber !5", "", models.PriorityNone, time.Time{}, ""},
+		{"Call the plumber @due:2026-11-01", "Call the plumber", "", models.PriorityNone, at(2026, time.November, 1, 0, 0), ""},
+		{"Call the plumber @due:tomorrow-3pm", "Call the plumber", "", models.PriorityNone, at(2026, time.October, 15, 15, 0), ""},
+		{"Call the plumber @due:next-friday", "Call the plumber", "", models.PriorityNone, at(2026, time.October, 16, 0, 0), ""},
+		{`Call the plumber @due:"tomorrow 3pm" +Home`, "Call the plumber", "Home", models.PriorityNone, at(2026, time.October, 15, 15, 0), ""},
+		{`@due:"in 2 weeks" Call the plumber`, "Call the plumber", "", models.PriorityNone, at(2026, time.October, 28, 0, 0), ""},
+		{"Call the plumber\nAsk about the boiler\n+NotAToken", "Call the plumber", "", models.PriorityNone, time.Time{}, "Ask about the boiler\n+NotAToken"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			entry, err := Parse(tt.input, now)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.input, err)
+			}
+			if entry.Title != tt.title || entry.Project != tt.project || entry.Priority != tt.priority || entry.Body != tt.body {
+				t.Errorf("Parse(%q) = %+v, want %q in %q, priority %d, body %q", tt.input, entry, tt.title, tt.project, tt.priority, tt.body)
+			}
+
+			var due time.Time
+			if entry.Due != nil {
+				due = *entry.Due
+			}
+			if !due.Equal(tt.due) {
+				t.Errorf("Parse(%q) due = %v, want %v", tt.input, due, tt.due)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []string{
+		"Call the plumber @due:someday",
+		"Call the plumber @due:some-day",
+		`Call the plumber @due:"some day"`,
+		"Call the plumber @due:2026-13-01",
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			if entry, err := Parse(input, now); err == nil {
+				t.Errorf("Parse(%q) = %+v, want an error", input, entry)
+			}
+		})
+	}
+}
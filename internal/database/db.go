@@ -2,22 +2,50 @@ package database
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"path/filepath"
 
+	"palco/internal/config"
+
 	_ "modernc.org/sqlite"
 )
 
+// legacyDatabasePath is where palco used to create its database, relative
+// to the working directory
+const legacyDatabasePath = "palco.db"
+
 type DB struct {
 	*sql.DB
 }
 
-func Run() *DB {
+// Options configures how Run opens the database
+type Options struct {
+	// Path to the database file. Takes precedence over PALCO_DB and the
+	// config file.
+	Path string
+
+	// MigrationsDir overrides the embedded migrations with SQL files read
+	// from disk. Leave empty to use the migrations compiled into the binary.
+	MigrationsDir string
+}
+
+func Run(opts Options) *DB {
 	// Get database path
-	dbPath, err := GetDatabasePath()
-	if err != nil {
-		log.Fatalf("Failed to get database path: %v", err)
+	dbPath := opts.Path
+	if dbPath == "" {
+		var err error
+		dbPath, err = GetDatabasePath()
+		if err != nil {
+			log.Fatalf("Failed to get database path: %v", err)
+		}
+	}
+
+	if err := prepareDatabasePath(dbPath); err != nil {
+		log.Fatalf("Failed to prepare database path: %v", err)
 	}
 
 	// Initialize database
@@ -27,17 +55,18 @@ func Run() *DB {
 	}
 
 	// Run migrations
-	migrationsPath, err := filepath.Abs("migrations")
-	if err != nil {
-		log.Fatalf("Failed to get migrations path: %v", err)
+	migrationsPath := ""
+	if opts.MigrationsDir != "" {
+		migrationsPath, err = filepath.Abs(opts.MigrationsDir)
+		if err != nil {
+			log.Fatalf("Failed to get migrations path: %v", err)
+		}
 	}
 
 	if err := RunMigrations(db, migrationsPath); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
-	log.Println("Database initialized successfully")
-
 	return db
 }
 
@@ -74,17 +103,136 @@ func (db *DB) Close() error {
 	return db.DB.Close()
 }
 
-// GetDataDir returns the user data directory path
+// GetDataDir returns the user data directory path ($XDG_DATA_HOME/palco)
 func GetDataDir() (string, error) {
-	// For now, use current directory. Later can be updated to use OS-specific paths
-	return ".", nil
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "palco"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", "palco"), nil
 }
 
-// GetDatabasePath returns the full path to the database file
+// GetDatabasePath returns the full path to the database file.
+// PALCO_DB wins over the config file, which wins over the data directory.
 func GetDatabasePath() (string, error) {
+	if path := os.Getenv("PALCO_DB"); path != "" {
+		return path, nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return "", err
+	}
+	if cfg.Database != "" {
+		return cfg.Database, nil
+	}
+
+	return defaultDatabasePath()
+}
+
+// defaultDatabasePath returns the database file in the data directory
+func defaultDatabasePath() (string, error) {
 	dataDir, err := GetDataDir()
 	if err != nil {
 		return "", err
 	}
 	return filepath.Join(dataDir, "palco.db"), nil
 }
+
+// prepareDatabasePath creates the parent directory of dbPath and, when the
+// database does not exist yet and dbPath is the default location, moves a
+// legacy ./palco.db over to it. A path chosen with --db, PALCO_DB or the
+// config file never takes over a palco.db that happens to be in the current
+// directory.
+func prepareDatabasePath(dbPath string) error {
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0o755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	if _, err := os.Stat(dbPath); !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	defaultPath, err := defaultDatabasePath()
+	if err != nil {
+		return err
+	}
+	if !samePath(dbPath, defaultPath) {
+		return nil
+	}
+
+	if _, err := os.Stat(legacyDatabasePath); err != nil {
+		return nil // Nothing to migrate
+	}
+
+	legacyAbs, err := filepath.Abs(legacyDatabasePath)
+	if err != nil {
+		return fmt.Errorf("failed to get legacy database path: %w", err)
+	}
+	dbAbs, err := filepath.Abs(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to get database path: %w", err)
+	}
+	if legacyAbs == dbAbs {
+		return nil
+	}
+
+	// Move the WAL and shared memory files along with the database so no
+	// committed writes are left behind
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		src := legacyAbs + suffix
+		if _, err := os.Stat(src); err != nil {
+			continue
+		}
+		if err := moveFile(src, dbAbs+suffix); err != nil {
+			return fmt.Errorf("failed to move %s: %w", src, err)
+		}
+	}
+
+	log.Printf("Moved %s to %s", legacyAbs, dbAbs)
+
+	return nil
+}
+
+// samePath reports whether a and b name the same file once made absolute
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+// moveFile renames src to dst, falling back to copy and remove when they
+// are on different filesystems
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+
+	in.Close()
+	return os.Remove(src)
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPrepareDatabasePathLegacy(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  func(dataDir, other string) string
+		moved bool
+	}{
+		{"default location", func(dataDir, _ string) string { return filepath.Join(dataDir, "palco", "palco.db") }, true},
+		{"chosen location", func(_, other string) string { return filepath.Join(other, "work.db") }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataDir, other, cwd := t.TempDir(), t.TempDir(), t.TempDir()
+			t.Setenv("XDG_DATA_HOME", dataDir)
+			t.Chdir(cwd)
+
+			for _, name := range []string{"palco.db", "palco.db-wal"} {
+				if err := os.WriteFile(name, []byte(name), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			dbPath := tt.path(dataDir, other)
+			if err := prepareDatabasePath(dbPath); err != nil {
+				t.Fatalf("prepareDatabasePath failed: %v", err)
+			}
+
+			_, legacyErr := os.Stat(filepath.Join(cwd, "palco.db"))
+			_, walErr := os.Stat(dbPath + "-wal")
+			if moved := os.IsNotExist(legacyErr) && walErr == nil; moved != tt.moved {
+				t.Errorf("legacy database moved = %v, want %v", moved, tt.moved)
+			}
+			if !tt.moved {
+				if _, err := os.Stat(dbPath); !os.IsNotExist(err) {
+					t.Errorf("%s was created, want it left for a new database", dbPath)
+				}
+			}
+		})
+	}
+}
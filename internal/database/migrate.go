@@ -3,25 +3,43 @@ package database
 import (
 	"fmt"
 
+	"palco/migrations"
+
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/sqlite3"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
 
-// RunMigrations runs all pending migrations
+// RunMigrations runs all pending migrations.
+// An empty migrationsPath uses the migrations embedded in the binary,
+// otherwise the SQL files are read from the given directory.
 func RunMigrations(db *DB, migrationsPath string) error {
 	driver, err := sqlite3.WithInstance(db.DB, &sqlite3.Config{})
 	if err != nil {
 		return fmt.Errorf("failed to create migration driver: %w", err)
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		fmt.Sprintf("file://%s", migrationsPath),
-		"sqlite3",
-		driver,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create migration instance: %w", err)
+	var m *migrate.Migrate
+	if migrationsPath == "" {
+		source, err := iofs.New(migrations.FS, ".")
+		if err != nil {
+			return fmt.Errorf("failed to open embedded migrations: %w", err)
+		}
+
+		m, err = migrate.NewWithInstance("iofs", source, "sqlite3", driver)
+		if err != nil {
+			return fmt.Errorf("failed to create migration instance: %w", err)
+		}
+	} else {
+		m, err = migrate.NewWithDatabaseInstance(
+			fmt.Sprintf("file://%s", migrationsPath),
+			"sqlite3",
+			driver,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create migration instance: %w", err)
+		}
 	}
 
 	// Run migrations
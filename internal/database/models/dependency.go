@@ -0,0 +1,8 @@
+package models
+
+// Dependency records that the task TaskID is blocked by the task
+// DependsOnID until that one is completed
+type Dependency struct {
+	TaskID      int64 `json:"task_id"`
+	DependsOnID int64 `json:"depends_on_id"`
+}
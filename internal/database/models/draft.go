@@ -0,0 +1,28 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Draft is an unfiled inbox entry waiting to be promoted to a task or note
+type Draft struct {
+	ID        int64     `json:"id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Title returns the first line of the draft, used as the task title when
+// the draft is promoted
+func (d Draft) Title() string {
+	title, _, _ := strings.Cut(strings.TrimSpace(d.Content), "\n")
+	return strings.TrimSpace(title)
+}
+
+// Body returns everything after the first line, used as the task
+// description when the draft is promoted
+func (d Draft) Body() string {
+	_, body, _ := strings.Cut(strings.TrimSpace(d.Content), "\n")
+	return strings.TrimSpace(body)
+}
@@ -0,0 +1,215 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// The sql.Null* types encode as {"String": .., "Valid": ..} objects. The
+// wire structs below mirror the models with pointers instead, so nullable
+// columns encode as null or their value.
+
+type projectJSON struct {
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description"`
+	DueDate     *time.Time `json:"due_date"`
+	Archived    bool       `json:"archived"`
+	ManualOrder bool       `json:"manual_order"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type taskJSON struct {
+	ID           int64      `json:"id"`
+	ProjectID    *int64     `json:"project_id"`
+	ParentTaskID *int64     `json:"parent_task_id"`
+	Title        string     `json:"title"`
+	Priority     int        `json:"priority"`
+	Completed    bool       `json:"completed"`
+	DueAt        *time.Time `json:"due_at"`
+	StartAt      *time.Time `json:"start_at"`
+	ScheduledFor *time.Time `json:"scheduled_for"`
+	Recurrence   *string    `json:"recurrence"`
+	Position     int64      `json:"position"`
+	StatusID     *int64     `json:"status_id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type noteJSON struct {
+	ID            int64     `json:"id"`
+	ProjectID     *int64    `json:"project_id"`
+	TaskID        *int64    `json:"task_id"`
+	Content       string    `json:"content"`
+	IsDescription bool      `json:"is_description"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (p Project) toJSON() projectJSON {
+	return projectJSON{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: stringPtr(p.Description),
+		DueDate:     timePtr(p.DueDate),
+		Archived:    p.Archived,
+		ManualOrder: p.ManualOrder,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+}
+
+// MarshalJSON encodes nullable columns as null or their value
+func (p Project) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.toJSON())
+}
+
+// UnmarshalJSON decodes the format written by MarshalJSON
+func (p *Project) UnmarshalJSON(data []byte) error {
+	var v projectJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*p = Project{
+		ID:          v.ID,
+		Name:        v.Name,
+		Description: nullString(v.Description),
+		DueDate:     nullTime(v.DueDate),
+		Archived:    v.Archived,
+		ManualOrder: v.ManualOrder,
+		CreatedAt:   v.CreatedAt,
+		UpdatedAt:   v.UpdatedAt,
+	}
+	return nil
+}
+
+func (t Task) toJSON() taskJSON {
+	return taskJSON{
+		ID:           t.ID,
+		ProjectID:    int64Ptr(t.ProjectID),
+		ParentTaskID: int64Ptr(t.ParentTaskID),
+		Title:        t.Title,
+		Priority:     t.Priority,
+		Completed:    t.Completed,
+		DueAt:        timePtr(t.DueAt),
+		StartAt:      timePtr(t.StartAt),
+		ScheduledFor: timePtr(t.ScheduledFor),
+		Recurrence:   stringPtr(t.Recurrence),
+		Position:     t.Position,
+		StatusID:     int64Ptr(t.StatusID),
+		CreatedAt:    t.CreatedAt,
+		UpdatedAt:    t.UpdatedAt,
+	}
+}
+
+// MarshalJSON encodes nullable columns as null or their value
+func (t Task) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.toJSON())
+}
+
+// UnmarshalJSON decodes the format written by MarshalJSON
+func (t *Task) UnmarshalJSON(data []byte) error {
+	var v taskJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*t = Task{
+		ID:           v.ID,
+		ProjectID:    nullInt64(v.ProjectID),
+		ParentTaskID: nullInt64(v.ParentTaskID),
+		Title:        v.Title,
+		Priority:     v.Priority,
+		Completed:    v.Completed,
+		DueAt:        nullTime(v.DueAt),
+		StartAt:      nullTime(v.StartAt),
+		ScheduledFor: nullTime(v.ScheduledFor),
+		Recurrence:   nullString(v.Recurrence),
+		Position:     v.Position,
+		StatusID:     nullInt64(v.StatusID),
+		CreatedAt:    v.CreatedAt,
+		UpdatedAt:    v.UpdatedAt,
+	}
+	return nil
+}
+
+func (n Note) toJSON() noteJSON {
+	return noteJSON{
+		ID:            n.ID,
+		ProjectID:     int64Ptr(n.ProjectID),
+		TaskID:        int64Ptr(n.TaskID),
+		Content:       n.Content,
+		IsDescription: n.IsDescription,
+		CreatedAt:     n.CreatedAt,
+		UpdatedAt:     n.UpdatedAt,
+	}
+}
+
+// MarshalJSON encodes nullable columns as null or their value
+func (n Note) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.toJSON())
+}
+
+// UnmarshalJSON decodes the format written by MarshalJSON
+func (n *Note) UnmarshalJSON(data []byte) error {
+	var v noteJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*n = Note{
+		ID:            v.ID,
+		ProjectID:     nullInt64(v.ProjectID),
+		TaskID:        nullInt64(v.TaskID),
+		Content:       v.Content,
+		IsDescription: v.IsDescription,
+		CreatedAt:     v.CreatedAt,
+		UpdatedAt:     v.UpdatedAt,
+	}
+	return nil
+}
+
+func stringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func int64Ptr(i sql.NullInt64) *int64 {
+	if !i.Valid {
+		return nil
+	}
+	return &i.Int64
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+func nullInt64(i *int64) sql.NullInt64 {
+	if i == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *i, Valid: true}
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"sort"
 	"time"
 )
 
@@ -11,6 +12,39 @@ type Project struct {
 	Description sql.NullString `json:"description"`
 	DueDate     sql.NullTime   `json:"due_date"`
 	Archived    bool           `json:"archived"`
+	ManualOrder bool           `json:"manual_order"` // Tasks in manual order instead of by priority
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 }
+
+// DueSoonDays is how many days ahead a project counts as due soon
+const DueSoonDays = 7
+
+// DaysUntilDue returns the whole days from now's date until the project's
+// due date, negative once it is overdue. ok is false when no due date is set.
+func (p Project) DaysUntilDue(now time.Time) (days int, ok bool) {
+	if !p.DueDate.Valid {
+		return 0, false
+	}
+	return DaysUntil(p.DueDate.Time, now), true
+}
+
+// DaysUntil counts the calendar days from now's date to date's. Stored
+// dates carry no time zone, so only the calendar day of each is compared.
+func DaysUntil(date, now time.Time) int {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return int(day.Sub(today).Hours() / 24)
+}
+
+// SortByDueDate orders projects by due date, soonest first, with projects
+// without one last. Ties keep their current order.
+func SortByDueDate(projects []Project) {
+	sort.SliceStable(projects, func(i, j int) bool {
+		a, b := projects[i].DueDate, projects[j].DueDate
+		if a.Valid != b.Valid {
+			return a.Valid
+		}
+		return a.Valid && a.Time.Before(b.Time)
+	})
+}
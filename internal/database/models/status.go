@@ -0,0 +1,168 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Status is a step of a project's workflow. Tasks in a done status count
+// as completed, and new or reopened tasks start in the default one.
+type Status struct {
+	ID        int64   `json:"id"`
+	ProjectID int64   `json:"project_id"`
+	Name      string  `json:"name"`
+	Position  int     `json:"position"`
+	Done      bool    `json:"done"`
+	Default   bool    `json:"default"`
+	Next      []int64 `json:"next"` // Statuses tasks can move to, any when empty
+}
+
+// Workflow is a project's statuses in order
+type Workflow []Status
+
+// DefaultWorkflow is the workflow new projects start with
+var DefaultWorkflow = Workflow{
+	{Name: "Backlog"},
+	{Name: "Todo", Default: true},
+	{Name: "In Progress"},
+	{Name: "In Review"},
+	{Name: "Blocked"},
+	{Name: "Done", Done: true},
+}
+
+// Find returns the status with the given ID
+func (w Workflow) Find(id int64) (Status, bool) {
+	for _, status := range w {
+		if status.ID == id {
+			return status, true
+		}
+	}
+	return Status{}, false
+}
+
+// ByName returns the status with the given name, ignoring case
+func (w Workflow) ByName(name string) (Status, bool) {
+	for _, status := range w {
+		if strings.EqualFold(status.Name, strings.TrimSpace(name)) {
+			return status, true
+		}
+	}
+	return Status{}, false
+}
+
+// Of returns the status of a task, the default one when it has none
+func (w Workflow) Of(task Task) Status {
+	if task.StatusID.Valid {
+		if status, ok := w.Find(task.StatusID.Int64); ok {
+			return status
+		}
+	}
+	return w.Initial(task.Completed)
+}
+
+// Initial returns the status a task starts in: the first done status for
+// completed tasks and the default status for the others
+func (w Workflow) Initial(completed bool) Status {
+	for _, status := range w {
+		if (completed && status.Done) || (!completed && status.Default) {
+			return status
+		}
+	}
+	return Status{}
+}
+
+// Label returns the status to show next to a task, empty when the task's
+// checkbox already says it: in the default status, or in the only done one
+func (w Workflow) Label(task Task) string {
+	status := w.Of(task)
+	if status.Default || (status.Done && w.doneCount() == 1) {
+		return ""
+	}
+	return status.Name
+}
+
+func (w Workflow) doneCount() int {
+	n := 0
+	for _, status := range w {
+		if status.Done {
+			n++
+		}
+	}
+	return n
+}
+
+// Allows reports whether a task can move from one status to another
+func (w Workflow) Allows(from, to Status) bool {
+	if len(from.Next) == 0 {
+		return from.ID != to.ID
+	}
+	for _, id := range from.Next {
+		if id == to.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// Cycle returns the status step places after from in workflow order,
+// wrapping around and skipping statuses from does not allow moving to. ok
+// is false when there is none.
+func (w Workflow) Cycle(from Status, step int) (Status, bool) {
+	start := -1
+	for i, status := range w {
+		if status.ID == from.ID {
+			start = i
+		}
+	}
+	if start < 0 || step == 0 {
+		return Status{}, false
+	}
+
+	n := len(w)
+	for i := 1; i < n; i++ {
+		next := w[((start+i*step)%n+n)%n]
+		if w.Allows(from, next) {
+			return next, true
+		}
+	}
+	return Status{}, false
+}
+
+// Validate checks that the workflow has unique names, exactly one default
+// status, which is not done, and at least one done status
+func (w Workflow) Validate() error {
+	if len(w) == 0 {
+		return fmt.Errorf("a workflow needs at least one status")
+	}
+
+	seen := make(map[string]bool, len(w))
+	defaults, done := 0, 0
+	for _, status := range w {
+		name := strings.ToLower(strings.TrimSpace(status.Name))
+		if name == "" {
+			return fmt.Errorf("status names cannot be empty")
+		}
+		if seen[name] {
+			return fmt.Errorf("status %q is listed twice", status.Name)
+		}
+		seen[name] = true
+
+		if status.Default {
+			if status.Done {
+				return fmt.Errorf("the default status %q cannot be a done status", status.Name)
+			}
+			defaults++
+		}
+		if status.Done {
+			done++
+		}
+	}
+
+	if defaults != 1 {
+		return fmt.Errorf("a workflow needs exactly one default status")
+	}
+	if done == 0 {
+		return fmt.Errorf("a workflow needs at least one done status")
+	}
+	return nil
+}
@@ -14,13 +14,122 @@ const (
 	PriorityUrgent = 4
 )
 
+// PriorityText returns the display name of a priority level
+func PriorityText(priority int) string {
+	switch priority {
+	case PriorityNone:
+		return "None"
+	case PriorityLow:
+		return "Low"
+	case PriorityMedium:
+		return "Medium"
+	case PriorityHigh:
+		return "High"
+	case PriorityUrgent:
+		return "Urgent"
+	default:
+		return "Unknown"
+	}
+}
+
 type Task struct {
-	ID           int64         `json:"id"`
-	ProjectID    sql.NullInt64 `json:"project_id"`
-	ParentTaskID sql.NullInt64 `json:"parent_task_id"`
-	Title        string        `json:"title"`
-	Priority     int           `json:"priority"`
-	Completed    bool          `json:"completed"`
-	CreatedAt    time.Time     `json:"created_at"`
-	UpdatedAt    time.Time     `json:"updated_at"`
+	ID           int64          `json:"id"`
+	ProjectID    sql.NullInt64  `json:"project_id"`
+	ParentTaskID sql.NullInt64  `json:"parent_task_id"`
+	Title        string         `json:"title"`
+	Priority     int            `json:"priority"`
+	Completed    bool           `json:"completed"`
+	DueAt        sql.NullTime   `json:"due_at"`
+	StartAt      sql.NullTime   `json:"start_at"`
+	ScheduledFor sql.NullTime   `json:"scheduled_for"`
+	Recurrence   sql.NullString `json:"recurrence"`
+	Position     int64          `json:"position"` // Order among siblings in manual ordering
+	StatusID     sql.NullInt64  `json:"status_id"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+// TaskDates are the planning dates of a task. They are wall-clock times
+// without a time zone; a date without a time of day is midnight.
+type TaskDates struct {
+	DueAt        *time.Time
+	StartAt      *time.Time
+	ScheduledFor *time.Time
+}
+
+// Dates returns the task's planning dates, for saving them back unchanged
+func (t Task) Dates() TaskDates {
+	return TaskDates{
+		DueAt:        timePtr(t.DueAt),
+		StartAt:      timePtr(t.StartAt),
+		ScheduledFor: timePtr(t.ScheduledFor),
+	}
+}
+
+// Repeat returns the task's repeat rule, or nil when it does not recur, for
+// saving it back unchanged
+func (t Task) Repeat() *string {
+	return stringPtr(t.Recurrence)
+}
+
+// DaysUntilDue returns the whole days from now's date until the task's due
+// date, negative once it is overdue. ok is false when no due date is set.
+func (t Task) DaysUntilDue(now time.Time) (days int, ok bool) {
+	if !t.DueAt.Valid {
+		return 0, false
+	}
+	return DaysUntil(t.DueAt.Time, now), true
+}
+
+// OrganizeTasksHierarchically reorganizes tasks so subtasks appear under their parents (recursively)
+func OrganizeTasksHierarchically(tasks []Task) ([]Task, []int) {
+	if len(tasks) == 0 {
+		return tasks, []int{}
+	}
+
+	// Build a map of parent ID to children
+	subtasksByParent := make(map[int64][]Task)
+	var rootTasks []Task
+
+	for _, task := range tasks {
+		if task.ParentTaskID.Valid {
+			parentID := task.ParentTaskID.Int64
+			subtasksByParent[parentID] = append(subtasksByParent[parentID], task)
+		} else {
+			rootTasks = append(rootTasks, task)
+		}
+	}
+
+	// Recursively build the hierarchical list
+	var result []Task
+	var depths []int
+
+	var addTaskAndChildren func(task Task, depth int)
+	addTaskAndChildren = func(task Task, depth int) {
+		result = append(result, task)
+		depths = append(depths, depth)
+
+		// Add children recursively
+		if children, exists := subtasksByParent[task.ID]; exists {
+			for _, child := range children {
+				addTaskAndChildren(child, depth+1)
+			}
+		}
+	}
+
+	// Add all root tasks and their descendants
+	for _, rootTask := range rootTasks {
+		addTaskAndChildren(rootTask, 0)
+	}
+
+	return result, depths
+}
+
+// FormatTaskDate formats a planning date as YYYY-MM-DD, adding the time of
+// day unless it is midnight
+func FormatTaskDate(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:04")
 }
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Kinds of items in the trash
+const (
+	TrashProject = "project"
+	TrashTask    = "task"
+	TrashNote    = "note"
+)
+
+// TrashItem is something deleted on its own, together with the tasks and
+// notes that were deleted with it and come back when it is restored
+type TrashItem struct {
+	Kind      string    `json:"kind"`
+	ID        int64     `json:"id"`
+	Title     string    `json:"title"`
+	Project   string    `json:"project,omitempty"` // Project of a task or note
+	DeletedAt time.Time `json:"deleted_at"`
+	Tasks     int       `json:"tasks"` // Tasks or subtasks deleted with it
+	Notes     int       `json:"notes"` // Notes deleted with it
+}
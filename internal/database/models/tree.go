@@ -0,0 +1,99 @@
+package models
+
+import "encoding/json"
+
+// TaskTree is a task together with its notes and subtasks
+type TaskTree struct {
+	Task
+	Notes    []Note
+	Subtasks []TaskTree
+}
+
+// ProjectTree is a project together with its notes and task trees
+type ProjectTree struct {
+	Project
+	Notes []Note
+	Tasks []TaskTree
+}
+
+// Description returns the content of the task's description note, if any
+func (t TaskTree) Description() *string {
+	for _, note := range t.Notes {
+		if note.IsDescription {
+			return &note.Content
+		}
+	}
+	return nil
+}
+
+// MarshalJSON flattens the task fields and nests notes and subtasks
+func (t TaskTree) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		taskJSON
+		Description *string    `json:"description"`
+		Notes       []Note     `json:"notes"`
+		Subtasks    []TaskTree `json:"subtasks"`
+	}{
+		taskJSON:    t.Task.toJSON(),
+		Description: t.Description(),
+		Notes:       nonNil(t.Notes),
+		Subtasks:    nonNil(t.Subtasks),
+	})
+}
+
+// MarshalJSON flattens the project fields and nests notes and tasks
+func (p ProjectTree) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		projectJSON
+		Notes []Note     `json:"notes"`
+		Tasks []TaskTree `json:"tasks"`
+	}{
+		projectJSON: p.Project.toJSON(),
+		Notes:       nonNil(p.Notes),
+		Tasks:       nonNil(p.Tasks),
+	})
+}
+
+// BuildTaskTrees nests tasks under their parents, keeping the order of
+// tasks within each sibling group. Tasks whose parent is not in the list
+// become roots. notesByTask attaches notes to each task.
+func BuildTaskTrees(tasks []Task, notesByTask map[int64][]Note) []TaskTree {
+	present := make(map[int64]bool, len(tasks))
+	for _, task := range tasks {
+		present[task.ID] = true
+	}
+
+	childrenByParent := make(map[int64][]Task)
+	var roots []Task
+	for _, task := range tasks {
+		if task.ParentTaskID.Valid && present[task.ParentTaskID.Int64] {
+			parentID := task.ParentTaskID.Int64
+			childrenByParent[parentID] = append(childrenByParent[parentID], task)
+		} else {
+			roots = append(roots, task)
+		}
+	}
+
+	var build func(task Task) TaskTree
+	build = func(task Task) TaskTree {
+		tree := TaskTree{Task: task, Notes: notesByTask[task.ID]}
+		for _, child := range childrenByParent[task.ID] {
+			tree.Subtasks = append(tree.Subtasks, build(child))
+		}
+		return tree
+	}
+
+	trees := make([]TaskTree, 0, len(roots))
+	for _, root := range roots {
+		trees = append(trees, build(root))
+	}
+	return trees
+}
+
+// nonNil turns a nil slice into an empty one so it encodes as []
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
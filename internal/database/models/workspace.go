@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// WorkspaceVersion is the current version of the workspace document format
+const WorkspaceVersion = 1
+
+// Workspace is a dump of the database, or of one project or task subtree
+// of it. IDs are only meaningful within the document: statuses, tasks,
+// notes and dependencies reference projects, statuses and tasks by the IDs
+// they have here.
+type Workspace struct {
+	Version      int          `json:"version"`
+	ExportedAt   time.Time    `json:"exported_at"`
+	Projects     []Project    `json:"projects"`
+	Statuses     []Status     `json:"statuses"`
+	Tasks        []Task       `json:"tasks"`
+	Notes        []Note       `json:"notes"`
+	Dependencies []Dependency `json:"dependencies"`
+}
@@ -0,0 +1,307 @@
+// Package dates parses the dates people type into forms and flags, such as
+// "tomorrow 3pm", "next monday", "in 3 days", "eom" or "2026-11-01",
+// relative to a given moment.
+package dates
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateLayout is how dates without a time of day are written
+const DateLayout = "2006-01-02"
+
+var (
+	isoDate     = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})$`)
+	isoDateTime = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})t(\d{1,2}:\d{2})\b`)
+	offset      = regexp.MustCompile(`^\+(\d+)([dwmy])$`)
+	inOffset    = regexp.MustCompile(`^in (\d+|a|an|one) (day|week|month|year)s?$`)
+	clock       = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+	dayMonth    = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)? ([a-z]+)$`)
+	monthDay    = regexp.MustCompile(`^([a-z]+) (\d{1,2})(?:st|nd|rd|th)?$`)
+)
+
+// Parse turns s into a time in loc, resolving relative dates against now.
+// It understands:
+//
+//   - today, tomorrow, yesterday
+//   - weekday names, the next such day with today included ("fri",
+//     "friday"), and "next friday", which is always after today
+//   - offsets: "+3d", "+2w", "+1m", "+1y", "in 3 days", "in a week"
+//   - "next week" (next Monday), "next month" and "next year" (their first day)
+//   - eod (today), and eow, eom and eoy: the last day of the week (Sunday),
+//     month or year
+//   - month names with a day, "nov 3" or "3 november", the next such date
+//   - ISO dates, "2026-11-01", with an optional "T14:30"
+//
+// Any of these may be followed by a time of day, optionally after "at":
+// "3pm", "3:30pm", "15:00", "noon" or "midnight". A time on its own means
+// today. Without a time the result is midnight.
+func Parse(s string, now time.Time, loc *time.Location) (time.Time, error) {
+	input := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	if input == "" {
+		return time.Time{}, fmt.Errorf("no date given")
+	}
+
+	// "2026-11-01T14:30" is a date followed by a time
+	input = isoDateTime.ReplaceAllString(input, "$1 $2")
+
+	now = now.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	day, rest, err := parseDay(input, today)
+	if err != nil {
+		return time.Time{}, invalid(s)
+	}
+
+	rest = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), "at "))
+	if rest == "" {
+		return day, nil
+	}
+
+	hour, minute, ok := parseClock(rest)
+	if !ok {
+		return time.Time{}, invalid(s)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc), nil
+}
+
+// parseDay reads the date at the start of input and returns what follows
+// it, which may be a time of day
+func parseDay(input string, today time.Time) (time.Time, string, error) {
+	// Try the longest date phrase first, so "next friday 3pm" reads
+	// "next friday" and leaves "3pm"
+	words := strings.Split(input, " ")
+	for n := min(len(words), 3); n > 0; n-- {
+		phrase := strings.Join(words[:n], " ")
+		if day, ok := parsePhrase(phrase, today); ok {
+			return day, strings.Join(words[n:], " "), nil
+		}
+	}
+
+	// A time of day on its own is today
+	if _, _, ok := parseClock(strings.TrimPrefix(input, "at ")); ok {
+		return today, input, nil
+	}
+
+	return time.Time{}, "", fmt.Errorf("unknown date")
+}
+
+// parsePhrase parses a date without a time of day
+func parsePhrase(phrase string, today time.Time) (time.Time, bool) {
+	switch phrase {
+	case "today", "tod":
+		return today, true
+	case "tomorrow", "tmr", "tom":
+		return today.AddDate(0, 0, 1), true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	case "eod":
+		return today, true
+	case "eow":
+		return today.AddDate(0, 0, (7-int(today.Weekday()))%7), true
+	case "eom":
+		return addMonths(firstOfMonth(today), 1).AddDate(0, 0, -1), true
+	case "eoy":
+		return time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, today.Location()), true
+	case "next week":
+		return nextWeekday(today, time.Monday, true), true
+	case "next month":
+		return addMonths(firstOfMonth(today), 1), true
+	case "next year":
+		return time.Date(today.Year()+1, time.January, 1, 0, 0, 0, 0, today.Location()), true
+	}
+
+	if name, next := strings.CutPrefix(phrase, "next "); next {
+		if day, ok := weekday(name); ok {
+			return nextWeekday(today, day, true), true
+		}
+		return time.Time{}, false
+	}
+	if day, ok := weekday(strings.TrimPrefix(phrase, "this ")); ok {
+		return nextWeekday(today, day, false), true
+	}
+
+	if m := offset.FindStringSubmatch(phrase); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return time.Time{}, false
+		}
+		return shift(today, n, m[2]), true
+	}
+	if m := inOffset.FindStringSubmatch(phrase); m != nil {
+		n := 1
+		if m[1] != "a" && m[1] != "an" && m[1] != "one" {
+			var err error
+			if n, err = strconv.Atoi(m[1]); err != nil {
+				return time.Time{}, false
+			}
+		}
+		return shift(today, n, m[2][:1]), true
+	}
+
+	if m := isoDate.FindStringSubmatch(phrase); m != nil {
+		return validDate(m[1], m[2], m[3], today.Location())
+	}
+
+	if m := monthDay.FindStringSubmatch(phrase); m != nil {
+		return nextMonthDay(today, m[1], m[2])
+	}
+	if m := dayMonth.FindStringSubmatch(phrase); m != nil {
+		return nextMonthDay(today, m[2], m[1])
+	}
+
+	return time.Time{}, false
+}
+
+// parseClock parses a time of day such as "3pm", "3:30pm", "15:00", "noon"
+// or "midnight"
+func parseClock(s string) (hour, minute int, ok bool) {
+	switch s {
+	case "noon", "midday":
+		return 12, 0, true
+	case "midnight":
+		return 0, 0, true
+	}
+
+	// "3 pm" reads like "3pm"
+	s = strings.Replace(s, " am", "am", 1)
+	s = strings.Replace(s, " pm", "pm", 1)
+
+	m := clock.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0, false
+	}
+
+	// A bare number is a day or a count, not a time
+	if m[2] == "" && m[3] == "" {
+		return 0, 0, false
+	}
+
+	hour, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	if minute > 59 {
+		return 0, 0, false
+	}
+
+	switch m[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		hour %= 12
+		if m[3] == "pm" {
+			hour += 12
+		}
+	default:
+		if hour > 23 {
+			return 0, 0, false
+		}
+	}
+
+	return hour, minute, true
+}
+
+// weekday parses a full or three-letter weekday name
+func weekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || name == full[:3] {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// nextWeekday returns the next day falling on weekday, today included
+// unless strict is set
+func nextWeekday(today time.Time, weekday time.Weekday, strict bool) time.Time {
+	days := (int(weekday) - int(today.Weekday()) + 7) % 7
+	if strict && days == 0 {
+		days = 7
+	}
+	return today.AddDate(0, 0, days)
+}
+
+// shift moves today by n days, weeks, months or years
+func shift(today time.Time, n int, unit string) time.Time {
+	switch unit {
+	case "w":
+		return today.AddDate(0, 0, 7*n)
+	case "m":
+		return addMonths(today, n)
+	case "y":
+		return addMonths(today, 12*n)
+	default:
+		return today.AddDate(0, 0, n)
+	}
+}
+
+// addMonths adds n months, keeping to the last day of a shorter month
+// rather than spilling into the next one (Jan 31 + 1 month is Feb 28)
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, 0, 0, 0, 0, t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return time.Date(first.Year(), first.Month(), min(t.Day(), last), 0, 0, 0, 0, t.Location())
+}
+
+func firstOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// nextMonthDay returns the next date on the named month and day, today
+// included
+func nextMonthDay(today time.Time, monthName, dayText string) (time.Time, bool) {
+	month, ok := parseMonth(monthName)
+	if !ok {
+		return time.Time{}, false
+	}
+	day, err := strconv.Atoi(dayText)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	for year := today.Year(); year <= today.Year()+4; year++ {
+		date := time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+		// Skip years where the day does not exist, such as Feb 29
+		if date.Month() != month || date.Before(today) {
+			continue
+		}
+		return date, true
+	}
+	return time.Time{}, false
+}
+
+// parseMonth parses a full or three-letter month name
+func parseMonth(name string) (time.Month, bool) {
+	for month := time.January; month <= time.December; month++ {
+		full := strings.ToLower(month.String())
+		if name == full || name == full[:3] || (month == time.September && name == "sept") {
+			return month, true
+		}
+	}
+	return 0, false
+}
+
+// validDate builds a date from its parts, rejecting ones such as
+// 2026-02-30 that time.Date would roll over
+func validDate(year, month, day string, loc *time.Location) (time.Time, bool) {
+	y, _ := strconv.Atoi(year)
+	m, _ := strconv.Atoi(month)
+	d, _ := strconv.Atoi(day)
+
+	date := time.Date(y, time.Month(m), d, 0, 0, 0, 0, loc)
+	if date.Year() != y || int(date.Month()) != m || date.Day() != d {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+func invalid(s string) error {
+	return fmt.Errorf("invalid date %q (expected e.g. today, tomorrow 3pm, next monday, in 3 days, eom or YYYY-MM-DD)", s)
+}
@@ -0,0 +1,128 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+// loc is ahead of UTC so tests catch dates taken in the wrong zone
+var loc = time.FixedZone("UTC+2", 2*60*60)
+
+func at(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, loc)
+}
+
+func TestParse(t *testing.T) {
+	wednesday := at(2026, time.October, 14, 10, 30)
+	monday := at(2026, time.October, 19, 10, 30)
+	sunday := at(2026, time.October, 18, 10, 30)
+
+	tests := []struct {
+		name  string
+		input string
+		now   time.Time
+		want  time.Time
+	}{
+		{"today", "today", wednesday, at(2026, time.October, 14, 0, 0)},
+		{"today in loc", "today", time.Date(2026, time.October, 14, 23, 30, 0, 0, time.UTC), at(2026, time.October, 15, 0, 0)},
+		{"tomorrow", "tomorrow", wednesday, at(2026, time.October, 15, 0, 0)},
+		{"tomorrow with time", "tomorrow 3pm", wednesday, at(2026, time.October, 15, 15, 0)},
+		{"tomorrow at time", "Tomorrow at 3:30pm", wednesday, at(2026, time.October, 15, 15, 30)},
+		{"yesterday", "yesterday", wednesday, at(2026, time.October, 13, 0, 0)},
+		{"time alone", "15:00", wednesday, at(2026, time.October, 14, 15, 0)},
+		{"noon", "noon", wednesday, at(2026, time.October, 14, 12, 0)},
+		{"midnight", "tomorrow midnight", wednesday, at(2026, time.October, 15, 0, 0)},
+		{"12am", "12am", wednesday, at(2026, time.October, 14, 0, 0)},
+		{"12pm", "12pm", wednesday, at(2026, time.October, 14, 12, 0)},
+		{"space before pm", "fri 3 pm", wednesday, at(2026, time.October, 16, 15, 0)},
+
+		{"weekday", "friday", wednesday, at(2026, time.October, 16, 0, 0)},
+		{"weekday short", "fri", wednesday, at(2026, time.October, 16, 0, 0)},
+		{"weekday today", "wednesday", wednesday, at(2026, time.October, 14, 0, 0)},
+		{"next weekday", "next monday", wednesday, at(2026, time.October, 19, 0, 0)},
+		{"next weekday on that day", "next monday", monday, at(2026, time.October, 26, 0, 0)},
+		{"weekday on that day", "monday", monday, at(2026, time.October, 19, 0, 0)},
+		{"next weekday with time", "next wednesday at 3pm", wednesday, at(2026, time.October, 21, 15, 0)},
+		{"next week", "next week", wednesday, at(2026, time.October, 19, 0, 0)},
+		{"next week on monday", "next week", monday, at(2026, time.October, 26, 0, 0)},
+		{"next month", "next month", wednesday, at(2026, time.November, 1, 0, 0)},
+		{"next year", "next year", wednesday, at(2027, time.January, 1, 0, 0)},
+
+		{"in days", "in 3 days", wednesday, at(2026, time.October, 17, 0, 0)},
+		{"in a week", "in a week", wednesday, at(2026, time.October, 21, 0, 0)},
+		{"in one month", "in one month", wednesday, at(2026, time.November, 14, 0, 0)},
+		{"plus days", "+3d", wednesday, at(2026, time.October, 17, 0, 0)},
+		{"plus weeks", "+2w", wednesday, at(2026, time.October, 28, 0, 0)},
+		{"plus month from jan 31", "+1m", at(2026, time.January, 31, 9, 0), at(2026, time.February, 28, 0, 0)},
+		{"plus month from jan 31 in leap year", "+1m", at(2028, time.January, 31, 9, 0), at(2028, time.February, 29, 0, 0)},
+		{"plus year from feb 29", "+1y", at(2028, time.February, 29, 9, 0), at(2029, time.February, 28, 0, 0)},
+
+		{"eod", "eod", wednesday, at(2026, time.October, 14, 0, 0)},
+		{"eow", "eow", wednesday, at(2026, time.October, 18, 0, 0)},
+		{"eow on sunday", "eow", sunday, at(2026, time.October, 18, 0, 0)},
+		{"eom", "eom", wednesday, at(2026, time.October, 31, 0, 0)},
+		{"eom in february", "eom", at(2026, time.February, 10, 9, 0), at(2026, time.February, 28, 0, 0)},
+		{"eom in leap february", "eom", at(2028, time.February, 10, 9, 0), at(2028, time.February, 29, 0, 0)},
+		{"eom on the last day", "eom", at(2026, time.January, 31, 9, 0), at(2026, time.January, 31, 0, 0)},
+		{"eoy", "eoy", wednesday, at(2026, time.December, 31, 0, 0)},
+
+		{"month day", "nov 3", wednesday, at(2026, time.November, 3, 0, 0)},
+		{"day month", "3rd november", wednesday, at(2026, time.November, 3, 0, 0)},
+		{"month day passed", "oct 1", wednesday, at(2027, time.October, 1, 0, 0)},
+		{"month day today", "oct 14", wednesday, at(2026, time.October, 14, 0, 0)},
+		{"feb 29 skips to leap year", "feb 29", wednesday, at(2028, time.February, 29, 0, 0)},
+
+		{"iso date", "2026-11-01", wednesday, at(2026, time.November, 1, 0, 0)},
+		{"iso date with time", "2026-11-01T14:30", wednesday, at(2026, time.November, 1, 14, 30)},
+		{"iso date lowercase t", "2026-11-01t09:05", wednesday, at(2026, time.November, 1, 9, 5)},
+		{"iso date at time", "2026-11-01 at 9am", wednesday, at(2026, time.November, 1, 9, 0)},
+		{"iso leap day", "2028-02-29", wednesday, at(2028, time.February, 29, 0, 0)},
+		{"extra spaces", "  next   friday  ", wednesday, at(2026, time.October, 16, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input, tt.now, loc)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.input, err)
+			}
+			if !got.Equal(tt.want) || got.Location() != loc {
+				t.Errorf("Parse(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	now := at(2026, time.October, 14, 10, 30)
+
+	tests := []string{
+		"",
+		"   ",
+		"someday",
+		"2026-02-30",
+		"2027-02-29",
+		"2026-13-01",
+		"2026-11-01T25:00",
+		"13pm",
+		"0am",
+		"24:00",
+		"12:60",
+		"tomorrow 3",
+		"tomorrow at",
+		"next",
+		"next blursday",
+		"in some days",
+		"+3x",
+		"feb 30",
+		"3 smarch",
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			if got, err := Parse(input, now, loc); err == nil {
+				t.Errorf("Parse(%q) = %v, want an error", input, got)
+			}
+		})
+	}
+}
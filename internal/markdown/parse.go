@@ -0,0 +1,307 @@
+package markdown
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"palco/internal/database/models"
+)
+
+// ProjectID is the document ID of the project returned by Parse
+const ProjectID = 1
+
+var (
+	checklistItem = regexp.MustCompile(`^([-*+]|\d+[.)]) \[([ xX])\]\s*(.*)$`)
+	heading       = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	dueDate       = regexp.MustCompile(`^\*\*Due:\*\*\s*(\d{4}-\d{2}-\d{2})`)
+	metaLine      = regexp.MustCompile(`^\*\*[^*]+:\*\*`)
+
+	priorityTag   = regexp.MustCompile(`\(p:([0-4])\)`)
+	priorityBadge = regexp.MustCompile("`(?i)(none|low|medium|high|urgent)`")
+	priorityBangs = regexp.MustCompile(`(^|\s)(!{1,4})(\s|$)`)
+)
+
+// parser holds the state while walking the document line by line
+type parser struct {
+	ws      *models.Workspace
+	project *models.Project
+
+	section     string   // Lowercased text of the current "## " heading
+	preamble    []string // Paragraph lines before the first section
+	notesBuffer []string // Lines of the project note being read
+
+	stack   []openItem // Items that can still receive children
+	current *openItem  // Item whose body is being read
+	body    []string
+}
+
+type openItem struct {
+	taskID        int64
+	markerIndent  int // Column of the list marker
+	contentIndent int // Column of the item text
+}
+
+// Parse reads a Markdown checklist into a workspace with a single project.
+// Nested "- [ ]" items become subtasks, "[x]" marks them completed and
+// "!"-runs, "(p:N)" or priority badges set their priority. Indented
+// paragraphs under an item become its description and indented
+// blockquotes become task notes. The first "# " heading names the
+// project, and paragraphs under a "## Notes" heading become project notes.
+func Parse(r io.Reader) (*models.Workspace, error) {
+	p := &parser{
+		ws: &models.Workspace{
+			Version:  models.WorkspaceVersion,
+			Projects: []models.Project{{ID: ProjectID}},
+		},
+	}
+	p.project = &p.ws.Projects[0]
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		p.line(expandTabs(strings.TrimRight(scanner.Text(), " \t\r")))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read markdown: %w", err)
+	}
+
+	p.flushItem()
+	p.flushProjectNote()
+
+	if description := joinParagraphs(p.preamble); description != "" {
+		p.project.Description.String = description
+		p.project.Description.Valid = true
+	}
+
+	return p.ws, nil
+}
+
+func (p *parser) line(line string) {
+	text := strings.TrimLeft(line, " ")
+	indent := len(line) - len(text)
+
+	if text == "" {
+		if p.current != nil {
+			p.body = append(p.body, "")
+		} else {
+			p.text("")
+		}
+		return
+	}
+
+	if m := checklistItem.FindStringSubmatch(text); m != nil {
+		p.flushItem()
+		p.item(indent, len(m[1])+5, m[2] != " ", m[3])
+		return
+	}
+
+	if p.current != nil && indent > p.current.markerIndent {
+		strip := min(indent, p.current.contentIndent)
+		p.body = append(p.body, line[strip:])
+		return
+	}
+
+	// Anything else at the list's level ends the list
+	p.flushItem()
+	p.stack = nil
+	p.text(text)
+}
+
+// item starts a new task under the closest less indented item
+func (p *parser) item(indent, markerWidth int, completed bool, title string) {
+	for len(p.stack) > 0 && p.stack[len(p.stack)-1].markerIndent >= indent {
+		p.stack = p.stack[:len(p.stack)-1]
+	}
+
+	title, priority := ParsePriority(title)
+
+	task := models.Task{
+		ID:        int64(len(p.ws.Tasks) + 1),
+		Title:     title,
+		Priority:  priority,
+		Completed: completed,
+	}
+	task.ProjectID.Int64, task.ProjectID.Valid = ProjectID, true
+	if len(p.stack) > 0 {
+		task.ParentTaskID.Int64 = p.stack[len(p.stack)-1].taskID
+		task.ParentTaskID.Valid = true
+	}
+	p.ws.Tasks = append(p.ws.Tasks, task)
+
+	p.stack = append(p.stack, openItem{
+		taskID:        task.ID,
+		markerIndent:  indent,
+		contentIndent: indent + markerWidth,
+	})
+	p.current = &p.stack[len(p.stack)-1]
+	p.body = nil
+}
+
+// flushItem turns the body of the current item into its description and
+// notes
+func (p *parser) flushItem() {
+	if p.current == nil {
+		return
+	}
+	taskID := p.current.taskID
+	p.current = nil
+
+	var description []string
+	for _, block := range splitBlocks(p.body) {
+		if isBlockquote(block) {
+			p.addNote(0, taskID, unquote(block), false)
+		} else {
+			description = append(description, strings.Join(block, "\n"), "")
+		}
+	}
+	p.body = nil
+
+	if content := joinParagraphs(description); content != "" {
+		p.addNote(0, taskID, content, true)
+	}
+}
+
+// text handles a line outside any checklist
+func (p *parser) text(text string) {
+	if m := heading.FindStringSubmatch(text); m != nil {
+		p.flushProjectNote()
+		if len(m[1]) == 1 && p.project.Name == "" {
+			p.project.Name = m[2]
+		} else {
+			p.section = strings.ToLower(m[2])
+		}
+		return
+	}
+
+	switch {
+	case p.section == "notes":
+		if text == "" {
+			p.flushProjectNote()
+		} else {
+			p.notesBuffer = append(p.notesBuffer, text)
+		}
+	case p.section != "":
+		// Text in other sections is not part of any task
+	case dueDate.MatchString(text):
+		if due, err := time.Parse("2006-01-02", dueDate.FindStringSubmatch(text)[1]); err == nil {
+			p.project.DueDate.Time, p.project.DueDate.Valid = due, true
+		}
+	case metaLine.MatchString(text):
+		// Progress and status lines written by Render
+	default:
+		p.preamble = append(p.preamble, text)
+	}
+}
+
+func (p *parser) flushProjectNote() {
+	if len(p.notesBuffer) == 0 {
+		return
+	}
+	p.addNote(ProjectID, 0, strings.Join(p.notesBuffer, "\n"), false)
+	p.notesBuffer = nil
+}
+
+func (p *parser) addNote(projectID, taskID int64, content string, isDescription bool) {
+	note := models.Note{
+		ID:            int64(len(p.ws.Notes) + 1),
+		Content:       content,
+		IsDescription: isDescription,
+	}
+	if projectID != 0 {
+		note.ProjectID.Int64, note.ProjectID.Valid = projectID, true
+	}
+	if taskID != 0 {
+		note.TaskID.Int64, note.TaskID.Valid = taskID, true
+	}
+	p.ws.Notes = append(p.ws.Notes, note)
+}
+
+// ParsePriority extracts a priority marker from a task title: "(p:N)", a
+// badge such as "`High`", or a run of one to four "!". It returns the
+// title without the marker and the priority, PriorityNone when absent.
+func ParsePriority(title string) (string, int) {
+	priority := models.PriorityNone
+
+	if m := priorityTag.FindStringSubmatch(title); m != nil {
+		priority, _ = strconv.Atoi(m[1])
+		title = priorityTag.ReplaceAllString(title, "")
+	} else if m := priorityBadge.FindStringSubmatch(title); m != nil {
+		for p := models.PriorityNone; p <= models.PriorityUrgent; p++ {
+			if strings.EqualFold(models.PriorityText(p), m[1]) {
+				priority = p
+			}
+		}
+		title = priorityBadge.ReplaceAllString(title, "")
+	} else if m := priorityBangs.FindStringSubmatch(title); m != nil {
+		priority = len(m[2])
+		title = priorityBangs.ReplaceAllString(title, " ")
+	}
+
+	return strings.Join(strings.Fields(title), " "), priority
+}
+
+// splitBlocks splits lines into blocks separated by blank lines. A
+// blockquote also starts a new block, as it can interrupt a paragraph.
+func splitBlocks(lines []string) [][]string {
+	var blocks [][]string
+	var block []string
+	for _, line := range lines {
+		if line == "" {
+			if len(block) > 0 {
+				blocks = append(blocks, block)
+				block = nil
+			}
+			continue
+		}
+		if len(block) > 0 && isQuoteLine(line) != isQuoteLine(block[0]) {
+			blocks = append(blocks, block)
+			block = nil
+		}
+		block = append(block, line)
+	}
+	if len(block) > 0 {
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+func isBlockquote(block []string) bool {
+	return len(block) > 0 && isQuoteLine(block[0])
+}
+
+func isQuoteLine(line string) bool {
+	return strings.HasPrefix(line, ">")
+}
+
+// unquote strips the "> " prefix from every line of a blockquote
+func unquote(block []string) string {
+	lines := make([]string, len(block))
+	for i, line := range block {
+		line = strings.TrimPrefix(line, ">")
+		lines[i] = strings.TrimPrefix(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// joinParagraphs joins lines, keeping a blank line between paragraphs
+func joinParagraphs(lines []string) string {
+	var paragraphs []string
+	for _, block := range splitBlocks(lines) {
+		paragraphs = append(paragraphs, strings.Join(block, "\n"))
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// expandTabs replaces leading tabs with four spaces each
+func expandTabs(line string) string {
+	i := 0
+	for i < len(line) && (line[i] == '\t' || line[i] == ' ') {
+		i++
+	}
+	return strings.ReplaceAll(line[:i], "\t", "    ") + line[i:]
+}
@@ -0,0 +1,212 @@
+package markdown
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"palco/internal/database/models"
+)
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		input    string
+		title    string
+		priority int
+	}{
+		{"Water plants", "Water plants", models.PriorityNone},
+		{"Fix leak !", "Fix leak", models.PriorityLow},
+		{"Fix leak !!!", "Fix leak", models.PriorityHigh},
+		{"!!!! Fix leak", "Fix leak", models.PriorityUrgent},
+		{"Fix !! leak", "Fix leak", models.PriorityMedium},
+		{"Fix leak !!!!!", "Fix leak !!!!!", models.PriorityNone},
+		{"Fix leak!!!", "Fix leak!!!", models.PriorityNone},
+		{"Fix leak (p:0)", "Fix leak", models.PriorityNone},
+		{"Fix leak (p:2)", "Fix leak", models.PriorityMedium},
+		{"(p:4) Fix leak", "Fix leak", models.PriorityUrgent},
+		{"Fix leak (p:5)", "Fix leak (p:5)", models.PriorityNone},
+		{"Fix leak `High`", "Fix leak", models.PriorityHigh},
+		{"Fix leak `urgent`", "Fix leak", models.PriorityUrgent},
+		{"Fix leak `None`", "Fix leak", models.PriorityNone},
+		{"Fix leak `Severe`", "Fix leak `Severe`", models.PriorityNone},
+		{"Fix leak (p:1) !!!", "Fix leak !!!", models.PriorityLow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			title, priority := ParsePriority(tt.input)
+			if title != tt.title || priority != tt.priority {
+				t.Errorf("ParsePriority(%q) = %q, %d, want %q, %d", tt.input, title, priority, tt.title, tt.priority)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	doc := strings.Join([]string{
+		"# Renovation",
+		"",
+		"Fix up the flat.",
+		"",
+		"**Due:** 2027-03-01  ",
+		"**Progress:** 2/7 tasks completed",
+		"",
+		"## Tasks",
+		"",
+		"- [ ] Kitchen !!",
+		"",
+		"  Strip the walls first.",
+		"",
+		"  > Landlord agreed",
+		"  - [x] Remove tiles (p:1)",
+		"    - [ ] Rent a grinder",
+		"  - [ ] Paint `High`",
+		"- [X] Bathroom",
+		"1. [ ] Hallway",
+		"\t- [ ] Lights",
+		"* [ ] Garden",
+		"",
+		"## Notes",
+		"",
+		"Budget is tight.",
+	}, "\n")
+
+	ws, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project := ws.Projects[0]
+	if project.Name != "Renovation" || project.Description.String != "Fix up the flat." {
+		t.Errorf("project = %q, %q, want Renovation with its description", project.Name, project.Description.String)
+	}
+	if got := project.DueDate.Time.Format("2006-01-02"); !project.DueDate.Valid || got != "2027-03-01" {
+		t.Errorf("project due = %v, want 2027-03-01", project.DueDate)
+	}
+
+	tests := []struct {
+		title     string
+		parent    int64
+		priority  int
+		completed bool
+	}{
+		{"Kitchen", 0, models.PriorityMedium, false},
+		{"Remove tiles", 1, models.PriorityLow, true},
+		{"Rent a grinder", 2, models.PriorityNone, false},
+		{"Paint", 1, models.PriorityHigh, false},
+		{"Bathroom", 0, models.PriorityNone, true},
+		{"Hallway", 0, models.PriorityNone, false},
+		{"Lights", 6, models.PriorityNone, false},
+		{"Garden", 0, models.PriorityNone, false},
+	}
+
+	if len(ws.Tasks) != len(tests) {
+		t.Fatalf("got %d tasks, want %d", len(ws.Tasks), len(tests))
+	}
+	for i, tt := range tests {
+		task := ws.Tasks[i]
+		if task.Title != tt.title || task.ParentTaskID.Int64 != tt.parent || task.Priority != tt.priority || task.Completed != tt.completed {
+			t.Errorf("task %d = %q, parent %d, priority %d, completed %v, want %q, %d, %d, %v",
+				task.ID, task.Title, task.ParentTaskID.Int64, task.Priority, task.Completed,
+				tt.title, tt.parent, tt.priority, tt.completed)
+		}
+	}
+
+	type note struct {
+		projectID, taskID int64
+		content           string
+		isDescription     bool
+	}
+	var notes []note
+	for _, n := range ws.Notes {
+		notes = append(notes, note{n.ProjectID.Int64, n.TaskID.Int64, n.Content, n.IsDescription})
+	}
+	want := []note{
+		{0, 1, "Landlord agreed", false},
+		{0, 1, "Strip the walls first.", true},
+		{ProjectID, 0, "Budget is tight.", false},
+	}
+	if !reflect.DeepEqual(notes, want) {
+		t.Errorf("notes = %+v, want %+v", notes, want)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"line too long", "- [ ] " + strings.Repeat("a", 2*1024*1024)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.doc)); err == nil {
+				t.Error("Parse succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestRenderParse(t *testing.T) {
+	task := func(id int64, title string, priority int, completed bool, subtasks ...models.TaskTree) models.TaskTree {
+		return models.TaskTree{
+			Task:     models.Task{ID: id, Title: title, Priority: priority, Completed: completed},
+			Subtasks: subtasks,
+		}
+	}
+
+	project := models.ProjectTree{Project: models.Project{Name: "Renovation"}}
+	project.Description.String, project.Description.Valid = "Fix up the flat.", true
+	project.Tasks = []models.TaskTree{
+		task(1, "Kitchen", models.PriorityUrgent, false,
+			task(2, "Remove tiles", models.PriorityNone, true,
+				task(3, "Rent a grinder", models.PriorityLow, false)),
+			task(4, "Paint", models.PriorityHigh, false)),
+		task(5, "Bathroom", models.PriorityMedium, true),
+	}
+	project.Tasks[0].Notes = []models.Note{
+		{Content: "Strip the walls first.\n\nThen sand.", IsDescription: true},
+		{Content: "Landlord agreed"},
+	}
+	project.Notes = []models.Note{{Content: "Budget is tight."}}
+
+	var buf bytes.Buffer
+	if err := Render(&buf, project); err != nil {
+		t.Fatal(err)
+	}
+	ws, err := Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ws.Projects[0]; got.Name != project.Name || got.Description != project.Description {
+		t.Errorf("project = %q, %q, want %q, %q", got.Name, got.Description.String, project.Name, project.Description.String)
+	}
+
+	// Parse numbers tasks in document order, which is the order above
+	parents := map[int64]int64{2: 1, 3: 2, 4: 1}
+	var walk func(trees []models.TaskTree)
+	walk = func(trees []models.TaskTree) {
+		for _, tree := range trees {
+			got := ws.Tasks[tree.ID-1]
+			if got.Title != tree.Title || got.Priority != tree.Priority || got.Completed != tree.Completed || got.ParentTaskID.Int64 != parents[tree.ID] {
+				t.Errorf("task %d = %+v, want %+v under %d", tree.ID, got, tree.Task, parents[tree.ID])
+			}
+			walk(tree.Subtasks)
+		}
+	}
+	walk(project.Tasks)
+	if len(ws.Tasks) != 5 {
+		t.Errorf("got %d tasks, want 5", len(ws.Tasks))
+	}
+
+	var contents []string
+	for _, note := range ws.Notes {
+		contents = append(contents, note.Content)
+	}
+	if want := []string{"Landlord agreed", "Strip the walls first.\n\nThen sand.", "Budget is tight."}; !reflect.DeepEqual(contents, want) {
+		t.Errorf("notes = %q, want %q", contents, want)
+	}
+}
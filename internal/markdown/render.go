@@ -0,0 +1,138 @@
+// Package markdown converts projects to and from Markdown checklists
+package markdown
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"palco/internal/database/models"
+)
+
+// Render writes project as a Markdown document: a header with the
+// description and due date, the task tree as nested checklists and the
+// project notes in their own section
+func Render(w io.Writer, project models.ProjectTree) error {
+	var bw strings.Builder
+
+	fmt.Fprintf(&bw, "# %s\n", project.Name)
+
+	if project.Description.Valid && project.Description.String != "" {
+		fmt.Fprintf(&bw, "\n%s\n", project.Description.String)
+	}
+
+	var meta []string
+	if project.DueDate.Valid {
+		meta = append(meta, fmt.Sprintf("**Due:** %s", project.DueDate.Time.Format("2006-01-02")))
+	}
+	if project.Archived {
+		meta = append(meta, "**Status:** Archived")
+	}
+	total, completed := countTasks(project.Tasks)
+	if total > 0 {
+		meta = append(meta, fmt.Sprintf("**Progress:** %d/%d tasks completed", completed, total))
+	}
+	if len(meta) > 0 {
+		fmt.Fprintf(&bw, "\n%s\n", strings.Join(meta, "  \n"))
+	}
+
+	if len(project.Tasks) > 0 {
+		fmt.Fprintf(&bw, "\n## Tasks\n\n")
+		for _, task := range project.Tasks {
+			renderTask(&bw, task, 0)
+		}
+	}
+
+	if len(project.Notes) > 0 {
+		fmt.Fprintf(&bw, "\n## Notes\n")
+		for _, note := range project.Notes {
+			fmt.Fprintf(&bw, "\n%s\n", note.Content)
+		}
+	}
+
+	_, err := io.WriteString(w, collapseBlankLines(bw.String()))
+	return err
+}
+
+// renderTask writes a checklist item followed by its description, notes
+// and subtasks, indented under the item
+func renderTask(w io.Writer, task models.TaskTree, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	check := " "
+	if task.Completed {
+		check = "x"
+	}
+
+	line := fmt.Sprintf("%s- [%s] %s", indent, check, task.Title)
+	if task.Priority != models.PriorityNone {
+		line += " " + PriorityBadge(task.Priority)
+	}
+	fmt.Fprintln(w, line)
+
+	// Body lines line up with the item text
+	bodyIndent := indent + "  "
+
+	if description := task.Description(); description != nil && *description != "" {
+		fmt.Fprintln(w)
+		writeIndented(w, bodyIndent, *description)
+		fmt.Fprintln(w)
+	}
+
+	for _, note := range task.Notes {
+		if note.IsDescription {
+			continue
+		}
+		writeIndented(w, bodyIndent+"> ", note.Content)
+		fmt.Fprintln(w)
+	}
+
+	for _, subtask := range task.Subtasks {
+		renderTask(w, subtask, depth+1)
+	}
+}
+
+// PriorityBadge returns the inline badge written after a task title, e.g.
+// "`High`"
+func PriorityBadge(priority int) string {
+	return "`" + models.PriorityText(priority) + "`"
+}
+
+// writeIndented writes every line of text with prefix
+func writeIndented(w io.Writer, prefix, text string) {
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		fmt.Fprintln(w, strings.TrimRight(prefix+line, " "))
+	}
+}
+
+// collapseBlankLines squeezes runs of blank lines into one and ends the
+// document with a single newline
+func collapseBlankLines(doc string) string {
+	var lines []string
+	blank := false
+	for _, line := range strings.Split(doc, "\n") {
+		if line == "" {
+			if blank {
+				continue
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
+
+func countTasks(tasks []models.TaskTree) (total, completed int) {
+	for _, task := range tasks {
+		total++
+		if task.Completed {
+			completed++
+		}
+		t, c := countTasks(task.Subtasks)
+		total += t
+		completed += c
+	}
+	return total, completed
+}
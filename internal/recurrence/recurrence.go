@@ -0,0 +1,334 @@
+// Package recurrence parses and evaluates the repeat rules of recurring
+// tasks. Rules are stored as a subset of iCalendar RRULEs, such as
+// "FREQ=WEEKLY;BYDAY=MO,WE", and can be typed as text like
+// "weekly on mon, wed" or "every 3 days after completion".
+package recurrence
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequencies
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+	Yearly  = "YEARLY"
+)
+
+// fromCompletion marks rules that repeat from the day a task is completed
+// rather than from its due date. RRULE has no such part, so it uses an
+// extension name.
+const fromCompletion = "X-FROM=COMPLETION"
+
+// Rule is a parsed repeat rule
+type Rule struct {
+	Freq     string
+	Interval int            // Repeat every Interval days, weeks, months or years
+	ByDay    []time.Weekday // Weekly rules: the weekdays to repeat on
+	MonthDay int            // Monthly and yearly rules: the day of the month, 0 for the start date's
+	// AfterCompletion counts the interval from the day the task is completed
+	AfterCompletion bool
+}
+
+var (
+	everyText    = regexp.MustCompile(`^every (\d+ )?(day|week|month|year)s?$`)
+	onWeekdays   = regexp.MustCompile(`^(.*) on ([a-z, ]+)$`)
+	monthDayText = regexp.MustCompile(`^(.*) on (?:the )?(\d{1,2})(?:st|nd|rd|th)?$`)
+	dayListText  = regexp.MustCompile(`\s*(?:,|\band\b)\s*|\s+`)
+)
+
+var dayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Parse reads a rule in its stored RRULE form, such as
+// "FREQ=MONTHLY;BYMONTHDAY=15"
+func Parse(s string) (Rule, error) {
+	rule := Rule{Interval: 1}
+	for _, part := range strings.Split(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "RRULE:"), ";") {
+		if part == fromCompletion {
+			rule.AfterCompletion = true
+			continue
+		}
+
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Rule{}, fmt.Errorf("invalid repeat rule %q", s)
+		}
+
+		switch key {
+		case "FREQ":
+			rule.Freq = value
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return Rule{}, fmt.Errorf("invalid interval %q in repeat rule", value)
+			}
+			rule.Interval = n
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				day := slices.Index(dayCodes, code)
+				if day < 0 {
+					return Rule{}, fmt.Errorf("invalid weekday %q in repeat rule", code)
+				}
+				rule.ByDay = append(rule.ByDay, time.Weekday(day))
+			}
+		case "BYMONTHDAY":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > 31 {
+				return Rule{}, fmt.Errorf("invalid day of month %q in repeat rule", value)
+			}
+			rule.MonthDay = n
+		default:
+			return Rule{}, fmt.Errorf("unsupported %s in repeat rule", key)
+		}
+	}
+
+	return rule, rule.validate()
+}
+
+// ParseText reads a rule typed by hand: "daily", "weekdays", "every 2
+// weeks", "weekly on mon, wed", "monthly on the 15th", "yearly", each
+// optionally followed by "after completion". A stored RRULE is accepted
+// as well.
+func ParseText(s string) (Rule, error) {
+	text := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	if strings.Contains(text, "freq=") {
+		return Parse(text)
+	}
+
+	var rule Rule
+	text, rule.AfterCompletion = strings.CutSuffix(text, " after completion")
+
+	if text == "weekdays" || text == "every weekday" {
+		rule.Freq, rule.Interval = Weekly, 1
+		rule.ByDay = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+		return rule, rule.validate()
+	}
+
+	if m := monthDayText.FindStringSubmatch(text); m != nil {
+		rule.MonthDay, _ = strconv.Atoi(m[2])
+		text = m[1]
+	} else if m := onWeekdays.FindStringSubmatch(text); m != nil {
+		for _, name := range dayListText.Split(strings.TrimSpace(m[2]), -1) {
+			day, ok := weekday(name)
+			if !ok {
+				return Rule{}, fmt.Errorf("unknown weekday %q in %q", name, s)
+			}
+			if !slices.Contains(rule.ByDay, day) {
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		}
+		slices.Sort(rule.ByDay)
+		text = m[1]
+	}
+
+	rule.Interval = 1
+	switch text {
+	case "daily", "every day":
+		rule.Freq = Daily
+	case "weekly", "every week":
+		rule.Freq = Weekly
+	case "monthly", "every month":
+		rule.Freq = Monthly
+	case "yearly", "annually", "every year":
+		rule.Freq = Yearly
+	default:
+		m := everyText.FindStringSubmatch(text)
+		if m == nil {
+			return Rule{}, fmt.Errorf("invalid repeat rule %q (expected e.g. daily, weekly on mon, wed, monthly on the 15th or every 3 days after completion)", s)
+		}
+		if m[1] != "" {
+			rule.Interval, _ = strconv.Atoi(strings.TrimSpace(m[1]))
+		}
+		rule.Freq = map[string]string{"day": Daily, "week": Weekly, "month": Monthly, "year": Yearly}[m[2]]
+	}
+
+	return rule, rule.validate()
+}
+
+func (r Rule) validate() error {
+	switch r.Freq {
+	case Daily, Weekly, Monthly, Yearly:
+	case "":
+		return fmt.Errorf("repeat rule has no frequency")
+	default:
+		return fmt.Errorf("unsupported frequency %q in repeat rule", r.Freq)
+	}
+
+	if r.Interval < 1 {
+		return fmt.Errorf("repeat interval must be at least 1")
+	}
+	if len(r.ByDay) > 0 && r.Freq != Weekly {
+		return fmt.Errorf("weekdays can only be given for weekly rules")
+	}
+	if r.MonthDay < 0 || r.MonthDay > 31 {
+		return fmt.Errorf("day of month %d in repeat rule is not between 1 and 31", r.MonthDay)
+	}
+	if r.MonthDay != 0 && r.Freq != Monthly && r.Freq != Yearly {
+		return fmt.Errorf("a day of the month can only be given for monthly and yearly rules")
+	}
+	if r.AfterCompletion && (len(r.ByDay) > 0 || r.MonthDay != 0) {
+		return fmt.Errorf("rules repeating after completion cannot name days")
+	}
+	return nil
+}
+
+// String returns the rule in its stored RRULE form
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			codes[i] = dayCodes[day]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.MonthDay != 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.MonthDay))
+	}
+	if r.AfterCompletion {
+		parts = append(parts, fromCompletion)
+	}
+	return strings.Join(parts, ";")
+}
+
+// Describe returns the rule as text that ParseText reads back, e.g.
+// "every 2 weeks on mon, thu"
+func (r Rule) Describe() string {
+	unit := map[string]string{Daily: "day", Weekly: "week", Monthly: "month", Yearly: "year"}[r.Freq]
+
+	var text string
+	switch {
+	case r.Interval > 1:
+		text = fmt.Sprintf("every %d %ss", r.Interval, unit)
+	case r.Freq == Yearly:
+		text = "yearly"
+	case r.Freq == Daily:
+		text = "daily"
+	default:
+		text = strings.ToLower(r.Freq)
+	}
+
+	if len(r.ByDay) > 0 {
+		names := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			names[i] = strings.ToLower(day.String()[:3])
+		}
+		text += " on " + strings.Join(names, ", ")
+	}
+	if r.MonthDay != 0 {
+		text += " on the " + ordinal(r.MonthDay)
+	}
+	if r.AfterCompletion {
+		text += " after completion"
+	}
+	return text
+}
+
+// Next returns the first occurrence after from, at from's time of day
+func (r Rule) Next(from time.Time) time.Time {
+	switch r.Freq {
+	case Daily:
+		return from.AddDate(0, 0, r.Interval)
+
+	case Weekly:
+		if len(r.ByDay) == 0 {
+			return from.AddDate(0, 0, 7*r.Interval)
+		}
+		// Weeks start on Monday; only every Interval-th week counts
+		weekStart := from.AddDate(0, 0, -(int(from.Weekday())+6)%7)
+		for days := 1; ; days++ {
+			next := from.AddDate(0, 0, days)
+			week := daysBetween(weekStart, next) / 7
+			if week%r.Interval == 0 && slices.Contains(r.ByDay, next.Weekday()) {
+				return next
+			}
+		}
+
+	case Monthly:
+		day := r.MonthDay
+		if day == 0 {
+			day = from.Day()
+		}
+		for months := 0; ; months += r.Interval {
+			next := onMonthDay(from, months, day)
+			if next.After(from) {
+				return next
+			}
+		}
+
+	default:
+		day := r.MonthDay
+		if day == 0 {
+			day = from.Day()
+		}
+		return onMonthDay(from, 12*r.Interval, day)
+	}
+}
+
+// Anchored pins a monthly or yearly rule to from's day of the month, so a
+// series starting on the 31st returns to it after a shorter month instead
+// of staying on the 28th. Other rules are returned unchanged.
+func (r Rule) Anchored(from time.Time) Rule {
+	if (r.Freq == Monthly || r.Freq == Yearly) && r.MonthDay == 0 && !r.AfterCompletion {
+		r.MonthDay = from.Day()
+	}
+	return r
+}
+
+// NextAfter returns the first occurrence after from that does not fall
+// before today, skipping occurrences missed while the task was overdue
+func (r Rule) NextAfter(from, today time.Time) time.Time {
+	next := r.Next(from)
+	for daysBetween(today, next) < 0 {
+		next = r.Next(next)
+	}
+	return next
+}
+
+// onMonthDay moves t by months, onto day or the last day of a shorter month
+func onMonthDay(t time.Time, months, day int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), 0, t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day, last)-1)
+}
+
+// daysBetween counts calendar days from a to b
+func daysBetween(a, b time.Time) int {
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
+
+// weekday parses a full or three-letter weekday name
+func weekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || name == full[:3] {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
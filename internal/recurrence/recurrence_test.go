@@ -0,0 +1,247 @@
+package recurrence
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+}
+
+func mustParse(t *testing.T, s string) Rule {
+	t.Helper()
+	rule, err := Parse(s)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", s, err)
+	}
+	return rule
+}
+
+func TestParse(t *testing.T) {
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+	tests := []struct {
+		input string
+		want  Rule
+	}{
+		{"FREQ=DAILY", Rule{Freq: Daily, Interval: 1}},
+		{"freq=daily;interval=3", Rule{Freq: Daily, Interval: 3}},
+		{"RRULE:FREQ=WEEKLY;BYDAY=MO,TH", Rule{Freq: Weekly, Interval: 1, ByDay: []time.Weekday{time.Monday, time.Thursday}}},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TU,WE,TH,FR", Rule{Freq: Weekly, Interval: 2, ByDay: weekdays}},
+		{"FREQ=MONTHLY;BYMONTHDAY=31", Rule{Freq: Monthly, Interval: 1, MonthDay: 31}},
+		{"FREQ=YEARLY;BYMONTHDAY=29", Rule{Freq: Yearly, Interval: 1, MonthDay: 29}},
+		{"FREQ=DAILY;INTERVAL=3;X-FROM=COMPLETION", Rule{Freq: Daily, Interval: 3, AfterCompletion: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := mustParse(t, tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+
+			// The stored form reads back to the same rule
+			if again := mustParse(t, got.String()); !reflect.DeepEqual(again, got) {
+				t.Errorf("Parse(%q) = %+v, want %+v", got.String(), again, got)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []string{
+		"",
+		"daily",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;COUNT=3",
+		"FREQ=DAILY;UNTIL=20270101",
+		"FREQ=MONTHLY;BYSETPOS=-1",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;INTERVAL=x",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYMONTHDAY=-1",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYMONTHDAY=3",
+		"FREQ=WEEKLY;BYDAY=MO;X-FROM=COMPLETION",
+		"FREQ=MONTHLY;BYMONTHDAY=15;X-FROM=COMPLETION",
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			if got, err := Parse(input); err == nil {
+				t.Errorf("Parse(%q) = %+v, want an error", input, got)
+			}
+		})
+	}
+}
+
+func TestParseText(t *testing.T) {
+	tests := []struct {
+		input    string
+		stored   string
+		describe string
+	}{
+		{"daily", "FREQ=DAILY", "daily"},
+		{"Every Day", "FREQ=DAILY", "daily"},
+		{"weekly", "FREQ=WEEKLY", "weekly"},
+		{"monthly", "FREQ=MONTHLY", "monthly"},
+		{"annually", "FREQ=YEARLY", "yearly"},
+		{"weekdays", "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", "weekly on mon, tue, wed, thu, fri"},
+		{"every 3 days", "FREQ=DAILY;INTERVAL=3", "every 3 days"},
+		{"every 6 months", "FREQ=MONTHLY;INTERVAL=6", "every 6 months"},
+		{"weekly on wed, mon", "FREQ=WEEKLY;BYDAY=MO,WE", "weekly on mon, wed"},
+		{"weekly on monday and friday", "FREQ=WEEKLY;BYDAY=MO,FR", "weekly on mon, fri"},
+		{"every 2 weeks on fri", "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR", "every 2 weeks on fri"},
+		{"monthly on the 15th", "FREQ=MONTHLY;BYMONTHDAY=15", "monthly on the 15th"},
+		{"monthly on 1", "FREQ=MONTHLY;BYMONTHDAY=1", "monthly on the 1st"},
+		{"every 3 days after completion", "FREQ=DAILY;INTERVAL=3;X-FROM=COMPLETION", "every 3 days after completion"},
+		{"FREQ=WEEKLY;BYDAY=SU", "FREQ=WEEKLY;BYDAY=SU", "weekly on sun"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			rule, err := ParseText(tt.input)
+			if err != nil {
+				t.Fatalf("ParseText(%q) failed: %v", tt.input, err)
+			}
+			if got := rule.String(); got != tt.stored {
+				t.Errorf("ParseText(%q) = %q, want %q", tt.input, got, tt.stored)
+			}
+			if got := rule.Describe(); got != tt.describe {
+				t.Errorf("Describe() = %q, want %q", got, tt.describe)
+			}
+
+			// The description reads back to the same rule
+			again, err := ParseText(rule.Describe())
+			if err != nil || !reflect.DeepEqual(again, rule) {
+				t.Errorf("ParseText(%q) = %+v, %v, want %+v", rule.Describe(), again, err, rule)
+			}
+		})
+	}
+}
+
+func TestParseTextRejects(t *testing.T) {
+	tests := []string{
+		"",
+		"sometimes",
+		"every 0 days",
+		"weekly on blursday",
+		"daily on mon",
+		"weekly on the 15th",
+		"weekly on mon after completion",
+		"monthly on the 32nd",
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			if got, err := ParseText(input); err == nil {
+				t.Errorf("ParseText(%q) = %+v, want an error", input, got)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		from time.Time
+		want time.Time
+	}{
+		{"daily", "FREQ=DAILY", date(2026, time.December, 31), date(2027, time.January, 1)},
+		{"every 3 days", "FREQ=DAILY;INTERVAL=3", date(2026, time.October, 30), date(2026, time.November, 2)},
+		{"weekly", "FREQ=WEEKLY", date(2026, time.October, 14), date(2026, time.October, 21)},
+		{"weekly on days, later this week", "FREQ=WEEKLY;BYDAY=MO,WE,FR", date(2026, time.October, 14), date(2026, time.October, 16)},
+		{"weekly on days, next week", "FREQ=WEEKLY;BYDAY=MO,WE,FR", date(2026, time.October, 16), date(2026, time.October, 19)},
+		{"every 2 weeks on days, same week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", date(2026, time.October, 14), date(2026, time.October, 15)},
+		{"every 2 weeks on days, skips a week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", date(2026, time.October, 15), date(2026, time.October, 26)},
+		{"every 2 weeks on sunday", "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU", date(2026, time.October, 18), date(2026, time.November, 1)},
+		{"monthly", "FREQ=MONTHLY", date(2026, time.October, 14), date(2026, time.November, 14)},
+		{"monthly from jan 31", "FREQ=MONTHLY;BYMONTHDAY=31", date(2027, time.January, 31), date(2027, time.February, 28)},
+		{"monthly back to the 31st", "FREQ=MONTHLY;BYMONTHDAY=31", date(2027, time.February, 28), date(2027, time.March, 31)},
+		{"monthly from jan 31 in leap year", "FREQ=MONTHLY;BYMONTHDAY=31", date(2028, time.January, 31), date(2028, time.February, 29)},
+		{"monthly unpinned stays on the 28th", "FREQ=MONTHLY", date(2027, time.February, 28), date(2027, time.March, 28)},
+		{"monthly on a later day this month", "FREQ=MONTHLY;BYMONTHDAY=15", date(2026, time.October, 10), date(2026, time.October, 15)},
+		{"monthly on an earlier day", "FREQ=MONTHLY;BYMONTHDAY=15", date(2026, time.October, 20), date(2026, time.November, 15)},
+		{"every 3 months from the 31st", "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=31", date(2027, time.January, 31), date(2027, time.April, 30)},
+		{"monthly across the year", "FREQ=MONTHLY", date(2026, time.December, 5), date(2027, time.January, 5)},
+		{"yearly", "FREQ=YEARLY", date(2026, time.October, 14), date(2027, time.October, 14)},
+		{"yearly from feb 29", "FREQ=YEARLY;BYMONTHDAY=29", date(2028, time.February, 29), date(2029, time.February, 28)},
+		{"yearly back to feb 29", "FREQ=YEARLY;BYMONTHDAY=29", date(2031, time.February, 28), date(2032, time.February, 29)},
+		{"every 2 years", "FREQ=YEARLY;INTERVAL=2", date(2026, time.March, 1), date(2028, time.March, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mustParse(t, tt.rule).Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		from  time.Time
+		today time.Time
+		want  time.Time
+	}{
+		{"not overdue", "FREQ=WEEKLY", date(2026, time.October, 14), date(2026, time.October, 14), date(2026, time.October, 21)},
+		{"skips missed days", "FREQ=DAILY", date(2026, time.October, 1), time.Date(2026, time.October, 14, 0, 0, 0, 0, time.UTC), date(2026, time.October, 14)},
+		{"skips missed weeks", "FREQ=WEEKLY;BYDAY=WE", date(2026, time.September, 30), date(2026, time.October, 15), date(2026, time.October, 21)},
+		{"skips missed months on the 31st", "FREQ=MONTHLY;BYMONTHDAY=31", date(2027, time.January, 31), date(2027, time.April, 2), date(2027, time.April, 30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mustParse(t, tt.rule).NextAfter(tt.from, tt.today); !got.Equal(tt.want) {
+				t.Errorf("NextAfter(%v, %v) = %v, want %v", tt.from, tt.today, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnchored(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		from time.Time
+		want string
+	}{
+		{"monthly", "FREQ=MONTHLY", date(2027, time.January, 31), "FREQ=MONTHLY;BYMONTHDAY=31"},
+		{"every 2 months", "FREQ=MONTHLY;INTERVAL=2", date(2027, time.January, 30), "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=30"},
+		{"yearly", "FREQ=YEARLY", date(2028, time.February, 29), "FREQ=YEARLY;BYMONTHDAY=29"},
+		{"already pinned", "FREQ=MONTHLY;BYMONTHDAY=15", date(2027, time.January, 31), "FREQ=MONTHLY;BYMONTHDAY=15"},
+		{"after completion", "FREQ=MONTHLY;X-FROM=COMPLETION", date(2027, time.January, 31), "FREQ=MONTHLY;X-FROM=COMPLETION"},
+		{"weekly", "FREQ=WEEKLY;BYDAY=MO", date(2027, time.January, 31), "FREQ=WEEKLY;BYDAY=MO"},
+		{"daily", "FREQ=DAILY", date(2027, time.January, 31), "FREQ=DAILY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mustParse(t, tt.rule).Anchored(tt.from).String(); got != tt.want {
+				t.Errorf("Anchored(%v) = %q, want %q", tt.from, got, tt.want)
+			}
+		})
+	}
+
+	// An anchored series returns to its day after a shorter month
+	rule := mustParse(t, "FREQ=MONTHLY").Anchored(date(2027, time.January, 31))
+	next := date(2027, time.January, 31)
+	var got []int
+	for range 4 {
+		next = rule.Next(next)
+		got = append(got, next.Day())
+	}
+	if want := []int{28, 31, 30, 31}; !reflect.DeepEqual(got, want) {
+		t.Errorf("days of an anchored monthly series = %v, want %v", got, want)
+	}
+}
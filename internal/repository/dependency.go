@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"palco/internal/database/models"
+)
+
+type DependencyRepository struct {
+	db *sql.DB
+}
+
+func NewDependencyRepository(db *sql.DB) *DependencyRepository {
+	return &DependencyRepository{db: db}
+}
+
+// Add records that a task is blocked by another one, which may be in a
+// different project. A task cannot block itself or a task it already
+// depends on, directly or through other tasks.
+func (r *DependencyRepository) Add(taskID, blockerID int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := addDependency(tx, taskID, blockerID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Remove unlinks a task from one of its blockers
+func (r *DependencyRepository) Remove(taskID, blockerID int64) error {
+	res, err := r.db.Exec(`DELETE FROM task_dependencies WHERE task_id = ? AND depends_on_id = ?`, taskID, blockerID)
+	if err != nil {
+		return fmt.Errorf("failed to remove dependency: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to remove dependency: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("task #%d is not blocked by task #%d", taskID, blockerID)
+	}
+
+	return nil
+}
+
+// Exists reports whether a task is blocked by another one
+func (r *DependencyRepository) Exists(taskID, blockerID int64) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM task_dependencies WHERE task_id = ? AND depends_on_id = ?)
+	`, taskID, blockerID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to get dependency: %w", err)
+	}
+	return exists, nil
+}
+
+// GetBlockers retrieves the tasks a task depends on, completed or not
+func (r *DependencyRepository) GetBlockers(taskID int64) ([]models.Task, error) {
+	return queryTasks(r.db, `
+		WHERE deleted_at IS NULL
+		AND id IN (SELECT depends_on_id FROM task_dependencies WHERE task_id = ?)
+	`, taskID)
+}
+
+// GetDependents retrieves the tasks that depend on a task
+func (r *DependencyRepository) GetDependents(taskID int64) ([]models.Task, error) {
+	return queryTasks(r.db, `
+		WHERE deleted_at IS NULL
+		AND id IN (SELECT task_id FROM task_dependencies WHERE depends_on_id = ?)
+	`, taskID)
+}
+
+// GetUnblockedBy retrieves the open tasks that depend on a task and have
+// no other open blockers, the ones completing it frees up
+func (r *DependencyRepository) GetUnblockedBy(taskID int64) ([]models.Task, error) {
+	return queryUnblockedBy(r.db, taskID)
+}
+
+// queryUnblockedBy reads what GetUnblockedBy returns, in or out of a
+// transaction
+func queryUnblockedBy(q queryer, taskID int64) ([]models.Task, error) {
+	return queryTasks(q, `
+		WHERE deleted_at IS NULL AND completed = 0
+		AND id IN (SELECT task_id FROM task_dependencies WHERE depends_on_id = ?)
+		AND NOT EXISTS (`+openBlockers+`)
+	`, taskID)
+}
+
+// GetBlockedIDs returns the IDs of the tasks in a project that have open
+// blockers
+func (r *DependencyRepository) GetBlockedIDs(projectID int64) (map[int64]bool, error) {
+	rows, err := r.db.Query(`
+		SELECT id FROM tasks
+		WHERE project_id = ? AND deleted_at IS NULL
+		AND EXISTS (`+openBlockers+`)
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocked tasks: %w", err)
+	}
+	defer rows.Close()
+
+	blocked := make(map[int64]bool)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		blocked[id] = true
+	}
+
+	return blocked, rows.Err()
+}
+
+// openBlockers selects the open blockers of the task in the enclosing
+// query on tasks
+const openBlockers = `
+	SELECT 1 FROM task_dependencies d
+	JOIN tasks blocker ON blocker.id = d.depends_on_id
+	WHERE d.task_id = tasks.id AND blocker.completed = 0 AND blocker.deleted_at IS NULL
+`
+
+// addDependency links taskID to blockerID, rejecting links that would make
+// a task wait on itself
+func addDependency(tx *sql.Tx, taskID, blockerID int64) error {
+	if taskID == blockerID {
+		return fmt.Errorf("a task cannot block itself")
+	}
+
+	for _, id := range []int64{taskID, blockerID} {
+		var exists bool
+		err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ? AND deleted_at IS NULL)`, id).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to get task: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("task #%d not found", id)
+		}
+	}
+
+	// Walk everything the blocker waits on; finding the task there means
+	// the new link would close a cycle
+	var cycle bool
+	err := tx.QueryRow(`
+		WITH RECURSIVE blockers(id) AS (
+			SELECT ?
+			UNION
+			SELECT d.depends_on_id FROM task_dependencies d JOIN blockers ON d.task_id = blockers.id
+		)
+		SELECT EXISTS (SELECT 1 FROM blockers WHERE id = ?)
+	`, blockerID, taskID).Scan(&cycle)
+	if err != nil {
+		return fmt.Errorf("failed to check dependencies: %w", err)
+	}
+	if cycle {
+		return fmt.Errorf("task #%d already depends on task #%d, linking them would create a cycle", blockerID, taskID)
+	}
+
+	res, err := tx.Exec(`INSERT OR IGNORE INTO task_dependencies (task_id, depends_on_id) VALUES (?, ?)`, taskID, blockerID)
+	if err != nil {
+		return fmt.Errorf("failed to add dependency: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to add dependency: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("task #%d is already blocked by task #%d", taskID, blockerID)
+	}
+
+	return nil
+}
+
+// queryDependencies reads the dependencies matching the where clause, in
+// the order they were added
+func queryDependencies(q queryer, where string, args ...any) ([]models.Dependency, error) {
+	rows, err := q.Query(`
+		SELECT task_id, depends_on_id
+		FROM task_dependencies
+		`+where+`
+		ORDER BY rowid
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	dependencies := []models.Dependency{}
+	for rows.Next() {
+		var dependency models.Dependency
+		if err := rows.Scan(&dependency.TaskID, &dependency.DependsOnID); err != nil {
+			return nil, fmt.Errorf("failed to scan dependency: %w", err)
+		}
+		dependencies = append(dependencies, dependency)
+	}
+
+	return dependencies, rows.Err()
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddDependencyRejects(t *testing.T) {
+	r := newRepos(t)
+	project := r.project(t, "Home")
+	a := r.task(t, project.ID, nil, "Buy paint")
+	b := r.task(t, project.ID, nil, "Paint walls")
+	c := r.task(t, project.ID, nil, "Hang pictures")
+
+	// c waits on b, which waits on a
+	if err := r.dependencies.Add(b.ID, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.dependencies.Add(c.ID, b.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		taskID    int64
+		blockerID int64
+		want      string
+	}{
+		{"self-dependency", a.ID, a.ID, "cannot block itself"},
+		{"direct cycle", a.ID, b.ID, "would create a cycle"},
+		{"transitive cycle", a.ID, c.ID, "would create a cycle"},
+		{"duplicate", b.ID, a.ID, "already blocked"},
+		{"unknown task", 999, a.ID, "not found"},
+		{"unknown blocker", a.ID, 999, "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.dependencies.Add(tt.taskID, tt.blockerID)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Add(%d, %d) = %v, want an error containing %q", tt.taskID, tt.blockerID, err, tt.want)
+			}
+		})
+	}
+
+	blockers, err := r.dependencies.GetBlockers(a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blockers) != 0 {
+		t.Errorf("task #%d blockers = %v, want none", a.ID, blockers)
+	}
+}
+
+func TestCompletingLastBlockerUnblocks(t *testing.T) {
+	r := newRepos(t)
+	home := r.project(t, "Home")
+	work := r.project(t, "Work")
+	paint := r.task(t, home.ID, nil, "Buy paint")
+	brushes := r.task(t, work.ID, nil, "Buy brushes")
+	walls := r.task(t, home.ID, nil, "Paint walls")
+	free := r.task(t, home.ID, nil, "Water plants")
+
+	// The walls wait on a blocker in each project
+	for _, blocker := range []int64{paint.ID, brushes.ID} {
+		if err := r.dependencies.Add(walls.ID, blocker); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	blocked := func() map[int64]bool {
+		t.Helper()
+		ids, err := r.dependencies.GetBlockedIDs(home.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ids
+	}
+	if got := blocked(); len(got) != 1 || !got[walls.ID] || got[free.ID] {
+		t.Fatalf("blocked = %v, want only task #%d", got, walls.ID)
+	}
+
+	now := time.Date(2027, time.April, 1, 9, 0, 0, 0, time.UTC)
+	completion, err := r.tasks.SetCompleted(paint.ID, true, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(completion.Unblocked) != 0 {
+		t.Errorf("unblocked = %v, want none while another blocker is open", completion.Unblocked)
+	}
+	if got := blocked(); !got[walls.ID] {
+		t.Errorf("blocked = %v, want task #%d still blocked", got, walls.ID)
+	}
+
+	completion, err = r.tasks.SetCompleted(brushes.ID, true, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(completion.Unblocked) != 1 || completion.Unblocked[0].ID != walls.ID {
+		t.Errorf("unblocked = %v, want task #%d", completion.Unblocked, walls.ID)
+	}
+	if got := blocked(); len(got) != 0 {
+		t.Errorf("blocked = %v, want none", got)
+	}
+
+	// Reopening a blocker blocks the task again
+	if _, err := r.tasks.SetCompleted(paint.ID, false, now); err != nil {
+		t.Fatal(err)
+	}
+	if got := blocked(); !got[walls.ID] {
+		t.Errorf("blocked = %v, want task #%d blocked again", got, walls.ID)
+	}
+
+	// A deleted blocker no longer blocks
+	if err := r.tasks.Delete(paint.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := blocked(); len(got) != 0 {
+		t.Errorf("blocked = %v, want none after the blocker is deleted", got)
+	}
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"palco/internal/database/models"
+)
+
+type DraftRepository struct {
+	db *sql.DB
+}
+
+func NewDraftRepository(db *sql.DB) *DraftRepository {
+	return &DraftRepository{db: db}
+}
+
+// Create creates a new draft
+func (r *DraftRepository) Create(content string) (*models.Draft, error) {
+	query := `
+		INSERT INTO drafts (content)
+		VALUES (?)
+		RETURNING id, content, created_at, updated_at
+	`
+
+	var draft models.Draft
+	err := r.db.QueryRow(query, content).Scan(
+		&draft.ID,
+		&draft.Content,
+		&draft.CreatedAt,
+		&draft.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create draft: %w", err)
+	}
+
+	return &draft, nil
+}
+
+// GetByID retrieves a draft by ID
+func (r *DraftRepository) GetByID(id int64) (*models.Draft, error) {
+	query := `
+		SELECT id, content, created_at, updated_at
+		FROM drafts
+		WHERE id = ?
+	`
+
+	var draft models.Draft
+	err := r.db.QueryRow(query, id).Scan(
+		&draft.ID,
+		&draft.Content,
+		&draft.CreatedAt,
+		&draft.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get draft: %w", err)
+	}
+
+	return &draft, nil
+}
+
+// GetAll retrieves all drafts, newest first
+func (r *DraftRepository) GetAll() ([]models.Draft, error) {
+	query := `
+		SELECT id, content, created_at, updated_at
+		FROM drafts
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get drafts: %w", err)
+	}
+	defer rows.Close()
+
+	var drafts []models.Draft
+	for rows.Next() {
+		var draft models.Draft
+		err := rows.Scan(
+			&draft.ID,
+			&draft.Content,
+			&draft.CreatedAt,
+			&draft.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan draft: %w", err)
+		}
+		drafts = append(drafts, draft)
+	}
+
+	return drafts, nil
+}
+
+// Update updates a draft
+func (r *DraftRepository) Update(id int64, content string) (*models.Draft, error) {
+	query := `
+		UPDATE drafts
+		SET content = ?
+		WHERE id = ?
+		RETURNING id, content, created_at, updated_at
+	`
+
+	var draft models.Draft
+	err := r.db.QueryRow(query, content, id).Scan(
+		&draft.ID,
+		&draft.Content,
+		&draft.CreatedAt,
+		&draft.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update draft: %w", err)
+	}
+
+	return &draft, nil
+}
+
+// Delete deletes a draft
+func (r *DraftRepository) Delete(id int64) error {
+	query := `DELETE FROM drafts WHERE id = ?`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete draft: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("draft not found")
+	}
+
+	return nil
+}
+
+// PromoteToTask turns a draft into a task of the project, or a subtask when
+// parentTaskID is set. The first line becomes the title and the rest the
+// description. The draft is removed in the same transaction.
+func (r *DraftRepository) PromoteToTask(id int64, projectID int64, parentTaskID *int64, priority int) (*models.Task, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	draft, err := deleteDraft(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	title := draft.Title()
+	if title == "" {
+		return nil, fmt.Errorf("draft is empty")
+	}
+
+	position, err := nextPosition(tx, projectID, parentTaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	statusID, err := statusFor(tx, projectID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	taskQuery := `
+		INSERT INTO tasks (project_id, parent_task_id, title, priority, position, status_id)
+		VALUES (?, ?, ?, ?, ?, ?)
+		RETURNING id, project_id, parent_task_id, title, priority, completed, position, status_id, created_at, updated_at
+	`
+
+	var task models.Task
+	err = tx.QueryRow(taskQuery, projectID, parentTaskID, title, priority, position, statusID).Scan(
+		&task.ID,
+		&task.ProjectID,
+		&task.ParentTaskID,
+		&task.Title,
+		&task.Priority,
+		&task.Completed,
+		&task.Position,
+		&task.StatusID,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create task: %w", err)
+	}
+
+	if body := draft.Body(); body != "" {
+		noteQuery := `
+			INSERT INTO notes (task_id, content, is_description)
+			VALUES (?, ?, 1)
+		`
+		if _, err := tx.Exec(noteQuery, task.ID, body); err != nil {
+			return nil, fmt.Errorf("failed to create description note: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &task, nil
+}
+
+// PromoteToNote turns a draft into a note on a project or on a task;
+// exactly one of projectID and taskID must be set. The draft is removed in
+// the same transaction.
+func (r *DraftRepository) PromoteToNote(id int64, projectID *int64, taskID *int64) (*models.Note, error) {
+	if (projectID == nil) == (taskID == nil) {
+		return nil, fmt.Errorf("a note needs either a project or a task")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	draft, err := deleteDraft(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO notes (project_id, task_id, content)
+		VALUES (?, ?, ?)
+		RETURNING id, project_id, task_id, content, is_description, created_at, updated_at
+	`
+
+	var note models.Note
+	err = tx.QueryRow(query, projectID, taskID, draft.Content).Scan(
+		&note.ID,
+		&note.ProjectID,
+		&note.TaskID,
+		&note.Content,
+		&note.IsDescription,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create note: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &note, nil
+}
+
+// deleteDraft removes a draft inside tx and returns it
+func deleteDraft(tx *sql.Tx, id int64) (*models.Draft, error) {
+	query := `
+		DELETE FROM drafts
+		WHERE id = ?
+		RETURNING id, content, created_at, updated_at
+	`
+
+	var draft models.Draft
+	err := tx.QueryRow(query, id).Scan(
+		&draft.ID,
+		&draft.Content,
+		&draft.CreatedAt,
+		&draft.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("draft not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete draft: %w", err)
+	}
+
+	return &draft, nil
+}
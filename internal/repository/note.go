@@ -69,7 +69,7 @@ func (r *NoteRepository) GetByProjectID(projectID int64) ([]models.Note, error)
 	query := `
 		SELECT id, project_id, task_id, content, is_description, created_at, updated_at
 		FROM notes
-		WHERE project_id = ?
+		WHERE project_id = ? AND deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 
@@ -105,7 +105,7 @@ func (r *NoteRepository) GetByTaskID(taskID int64) ([]models.Note, error) {
 	query := `
 		SELECT id, project_id, task_id, content, is_description, created_at, updated_at
 		FROM notes
-		WHERE task_id = ?
+		WHERE task_id = ? AND deleted_at IS NULL
 		ORDER BY is_description DESC, created_at DESC
 	`
 
@@ -141,7 +141,7 @@ func (r *NoteRepository) GetTaskDescription(taskID int64) (*models.Note, error)
 	query := `
 		SELECT id, project_id, task_id, content, is_description, created_at, updated_at
 		FROM notes
-		WHERE task_id = ? AND is_description = 1
+		WHERE task_id = ? AND is_description = 1 AND deleted_at IS NULL
 		LIMIT 1
 	`
 
@@ -170,7 +170,7 @@ func (r *NoteRepository) UpdateTaskDescription(taskID int64, content string) err
 	query := `
 		UPDATE notes
 		SET content = ?
-		WHERE task_id = ? AND is_description = 1
+		WHERE task_id = ? AND is_description = 1 AND deleted_at IS NULL
 	`
 
 	result, err := r.db.Exec(query, content, taskID)
@@ -195,7 +195,7 @@ func (r *NoteRepository) Update(id int64, content string) (*models.Note, error)
 	query := `
 		UPDATE notes
 		SET content = ?
-		WHERE id = ?
+		WHERE id = ? AND deleted_at IS NULL
 		RETURNING id, project_id, task_id, content, is_description, created_at, updated_at
 	`
 
@@ -216,11 +216,11 @@ func (r *NoteRepository) Update(id int64, content string) (*models.Note, error)
 	return &note, nil
 }
 
-// Delete deletes a note
+// Delete moves a note to the trash
 func (r *NoteRepository) Delete(id int64) error {
-	query := `DELETE FROM notes WHERE id = ?`
+	query := `UPDATE notes SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`
 
-	result, err := r.db.Exec(query, id)
+	result, err := r.db.Exec(query, trashTimestamp(), id)
 	if err != nil {
 		return fmt.Errorf("failed to delete note: %w", err)
 	}
@@ -14,21 +14,28 @@ func NewProjectRepository(db *sql.DB) *ProjectRepository {
 	return &ProjectRepository{db: db}
 }
 
-// Create creates a new project
+// Create creates a new project with the default workflow
 func (r *ProjectRepository) Create(name string, description *string, dueDate *string) (*models.Project, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO projects (name, description, due_date)
 		VALUES (?, ?, ?)
-		RETURNING id, name, description, due_date, archived, created_at, updated_at
+		RETURNING id, name, description, due_date, archived, manual_order, created_at, updated_at
 	`
 
 	var project models.Project
-	err := r.db.QueryRow(query, name, description, dueDate).Scan(
+	err = tx.QueryRow(query, name, description, dueDate).Scan(
 		&project.ID,
 		&project.Name,
 		&project.Description,
 		&project.DueDate,
 		&project.Archived,
+		&project.ManualOrder,
 		&project.CreatedAt,
 		&project.UpdatedAt,
 	)
@@ -36,15 +43,23 @@ func (r *ProjectRepository) Create(name string, description *string, dueDate *st
 		return nil, fmt.Errorf("failed to create project: %w", err)
 	}
 
+	if _, err := createWorkflow(tx, project.ID, models.DefaultWorkflow); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return &project, nil
 }
 
 // GetByID retrieves a project by ID
 func (r *ProjectRepository) GetByID(id int64) (*models.Project, error) {
 	query := `
-		SELECT id, name, description, due_date, archived, created_at, updated_at
+		SELECT id, name, description, due_date, archived, manual_order, created_at, updated_at
 		FROM projects
-		WHERE id = ?
+		WHERE id = ? AND deleted_at IS NULL
 	`
 
 	var project models.Project
@@ -54,9 +69,42 @@ func (r *ProjectRepository) GetByID(id int64) (*models.Project, error) {
 		&project.Description,
 		&project.DueDate,
 		&project.Archived,
+		&project.ManualOrder,
+		&project.CreatedAt,
+		&project.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return &project, nil
+}
+
+// GetByName retrieves the most recently created project with the given name
+// (case-insensitive). Returns nil when no project matches.
+func (r *ProjectRepository) GetByName(name string) (*models.Project, error) {
+	query := `
+		SELECT id, name, description, due_date, archived, manual_order, created_at, updated_at
+		FROM projects
+		WHERE name = ? COLLATE NOCASE AND deleted_at IS NULL
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`
+
+	var project models.Project
+	err := r.db.QueryRow(query, name).Scan(
+		&project.ID,
+		&project.Name,
+		&project.Description,
+		&project.DueDate,
+		&project.Archived,
+		&project.ManualOrder,
 		&project.CreatedAt,
 		&project.UpdatedAt,
 	)
+	if err == sql.ErrNoRows {
+		return nil, nil // No project found
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get project: %w", err)
 	}
@@ -67,8 +115,9 @@ func (r *ProjectRepository) GetByID(id int64) (*models.Project, error) {
 // GetAll retrieves all projects
 func (r *ProjectRepository) GetAll() ([]models.Project, error) {
 	query := `
-		SELECT id, name, description, due_date, archived, created_at, updated_at
+		SELECT id, name, description, due_date, archived, manual_order, created_at, updated_at
 		FROM projects
+		WHERE deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 
@@ -87,6 +136,7 @@ func (r *ProjectRepository) GetAll() ([]models.Project, error) {
 			&project.Description,
 			&project.DueDate,
 			&project.Archived,
+			&project.ManualOrder,
 			&project.CreatedAt,
 			&project.UpdatedAt,
 		)
@@ -104,8 +154,8 @@ func (r *ProjectRepository) Update(id int64, name string, description *string, d
 	query := `
 		UPDATE projects
 		SET name = ?, description = ?, due_date = ?
-		WHERE id = ?
-		RETURNING id, name, description, due_date, archived, created_at, updated_at
+		WHERE id = ? AND deleted_at IS NULL
+		RETURNING id, name, description, due_date, archived, manual_order, created_at, updated_at
 	`
 
 	var project models.Project
@@ -115,6 +165,7 @@ func (r *ProjectRepository) Update(id int64, name string, description *string, d
 		&project.Description,
 		&project.DueDate,
 		&project.Archived,
+		&project.ManualOrder,
 		&project.CreatedAt,
 		&project.UpdatedAt,
 	)
@@ -125,11 +176,17 @@ func (r *ProjectRepository) Update(id int64, name string, description *string, d
 	return &project, nil
 }
 
-// Delete deletes a project
+// Delete moves a project with its tasks and notes to the trash
 func (r *ProjectRepository) Delete(id int64) error {
-	query := `DELETE FROM projects WHERE id = ?`
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	result, err := r.db.Exec(query, id)
+	deletedAt := trashTimestamp()
+
+	result, err := tx.Exec(`UPDATE projects SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`, deletedAt, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete project: %w", err)
 	}
@@ -143,12 +200,31 @@ func (r *ProjectRepository) Delete(id int64) error {
 		return fmt.Errorf("project not found")
 	}
 
+	// Notes first, while the tasks they belong to are still live
+	_, err = tx.Exec(`
+		UPDATE notes SET deleted_at = ?
+		WHERE deleted_at IS NULL
+		AND (project_id = ? OR task_id IN (SELECT id FROM tasks WHERE project_id = ? AND deleted_at IS NULL))
+	`, deletedAt, id, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete project notes: %w", err)
+	}
+
+	_, err = tx.Exec(`UPDATE tasks SET deleted_at = ? WHERE project_id = ? AND deleted_at IS NULL`, deletedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete project tasks: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return nil
 }
 
 // Archive archives a project
 func (r *ProjectRepository) Archive(id int64) error {
-	query := `UPDATE projects SET archived = 1 WHERE id = ?`
+	query := `UPDATE projects SET archived = 1 WHERE id = ? AND deleted_at IS NULL`
 
 	result, err := r.db.Exec(query, id)
 	if err != nil {
@@ -169,7 +245,7 @@ func (r *ProjectRepository) Archive(id int64) error {
 
 // Unarchive unarchives a project
 func (r *ProjectRepository) Unarchive(id int64) error {
-	query := `UPDATE projects SET archived = 0 WHERE id = ?`
+	query := `UPDATE projects SET archived = 0 WHERE id = ? AND deleted_at IS NULL`
 
 	result, err := r.db.Exec(query, id)
 	if err != nil {
@@ -188,12 +264,34 @@ func (r *ProjectRepository) Unarchive(id int64) error {
 	return nil
 }
 
+// SetManualOrder switches a project between listing its tasks in their
+// manual order and by priority
+func (r *ProjectRepository) SetManualOrder(id int64, manual bool) error {
+	query := `UPDATE projects SET manual_order = ? WHERE id = ? AND deleted_at IS NULL`
+
+	result, err := r.db.Exec(query, manual, id)
+	if err != nil {
+		return fmt.Errorf("failed to set task order: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("project not found")
+	}
+
+	return nil
+}
+
 // GetAllActive retrieves all active (non-archived) projects
 func (r *ProjectRepository) GetAllActive() ([]models.Project, error) {
 	query := `
-		SELECT id, name, description, due_date, archived, created_at, updated_at
+		SELECT id, name, description, due_date, archived, manual_order, created_at, updated_at
 		FROM projects
-		WHERE archived = 0
+		WHERE archived = 0 AND deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 
@@ -212,6 +310,7 @@ func (r *ProjectRepository) GetAllActive() ([]models.Project, error) {
 			&project.Description,
 			&project.DueDate,
 			&project.Archived,
+			&project.ManualOrder,
 			&project.CreatedAt,
 			&project.UpdatedAt,
 		)
@@ -227,9 +326,9 @@ func (r *ProjectRepository) GetAllActive() ([]models.Project, error) {
 // GetAllArchived retrieves all archived projects
 func (r *ProjectRepository) GetAllArchived() ([]models.Project, error) {
 	query := `
-		SELECT id, name, description, due_date, archived, created_at, updated_at
+		SELECT id, name, description, due_date, archived, manual_order, created_at, updated_at
 		FROM projects
-		WHERE archived = 1
+		WHERE archived = 1 AND deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 
@@ -248,6 +347,7 @@ func (r *ProjectRepository) GetAllArchived() ([]models.Project, error) {
 			&project.Description,
 			&project.DueDate,
 			&project.Archived,
+			&project.ManualOrder,
 			&project.CreatedAt,
 			&project.UpdatedAt,
 		)
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"palco/internal/database"
+	"palco/internal/database/models"
+)
+
+// openTestDB opens a fresh in-memory database with the embedded migrations
+// applied. Every test gets its own database, shared by the connections of
+// the pool.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := database.New(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := database.RunMigrations(db, ""); err != nil {
+		t.Fatal(err)
+	}
+	return db.DB
+}
+
+// repos bundles the repositories tests work with
+type repos struct {
+	projects     *ProjectRepository
+	tasks        *TaskRepository
+	notes        *NoteRepository
+	dependencies *DependencyRepository
+	statuses     *StatusRepository
+	workspace    *WorkspaceRepository
+}
+
+func newRepos(t *testing.T) repos {
+	db := openTestDB(t)
+	return repos{
+		projects:     NewProjectRepository(db),
+		tasks:        NewTaskRepository(db),
+		notes:        NewNoteRepository(db),
+		dependencies: NewDependencyRepository(db),
+		statuses:     NewStatusRepository(db),
+		workspace:    NewWorkspaceRepository(db),
+	}
+}
+
+func (r repos) project(t *testing.T, name string) *models.Project {
+	t.Helper()
+	project, err := r.projects.Create(name, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return project
+}
+
+func (r repos) task(t *testing.T, projectID int64, parentID *int64, title string) *models.Task {
+	t.Helper()
+	task, err := r.tasks.Create(projectID, parentID, title, nil, models.PriorityNone, models.TaskDates{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return task
+}
@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"palco/internal/database/models"
+	"strings"
+	"time"
+)
+
+type StatusRepository struct {
+	db *sql.DB
+}
+
+func NewStatusRepository(db *sql.DB) *StatusRepository {
+	return &StatusRepository{db: db}
+}
+
+// GetWorkflow retrieves a project's statuses in order
+func (r *StatusRepository) GetWorkflow(projectID int64) (models.Workflow, error) {
+	return queryStatuses(r.db, "WHERE project_id = ?", projectID)
+}
+
+// SetWorkflow replaces a project's workflow. A status whose name is still
+// in the workflow keeps its ID and its tasks; tasks in removed statuses move
+// to the default status, or the first done one when they are completed.
+// transitions maps status names to the statuses they allow moving to;
+// statuses left out allow every move.
+func (r *StatusRepository) SetWorkflow(projectID int64, workflow models.Workflow, transitions map[string][]string) (models.Workflow, error) {
+	if err := workflow.Validate(); err != nil {
+		return nil, err
+	}
+	for from, targets := range transitions {
+		if _, ok := workflow.ByName(from); !ok {
+			return nil, fmt.Errorf("status %q is not in the workflow", from)
+		}
+		for _, to := range targets {
+			if _, ok := workflow.ByName(to); !ok {
+				return nil, fmt.Errorf("status %q is not in the workflow", to)
+			}
+			if strings.EqualFold(strings.TrimSpace(from), strings.TrimSpace(to)) {
+				return nil, fmt.Errorf("status %q cannot lead to itself", from)
+			}
+		}
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM projects WHERE id = ? AND deleted_at IS NULL)`, projectID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("project not found")
+	}
+
+	current, err := queryStatuses(tx, "WHERE project_id = ?", projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the statuses that stay and add the new ones
+	ids := make(map[string]int64, len(workflow))
+	kept := make(map[int64]bool, len(workflow))
+	for i, status := range workflow {
+		name := strings.TrimSpace(status.Name)
+
+		id := int64(0)
+		if existing, ok := current.ByName(name); ok {
+			id = existing.ID
+			_, err = tx.Exec(`
+				UPDATE statuses SET name = ?, position = ?, is_done = ?, is_default = ?
+				WHERE id = ?
+			`, name, i+1, status.Done, status.Default, id)
+		} else {
+			err = tx.QueryRow(`
+				INSERT INTO statuses (project_id, name, position, is_done, is_default)
+				VALUES (?, ?, ?, ?, ?)
+				RETURNING id
+			`, projectID, name, i+1, status.Done, status.Default).Scan(&id)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to save status %q: %w", name, err)
+		}
+
+		ids[strings.ToLower(name)] = id
+		kept[id] = true
+	}
+
+	// Tasks in trash count too, so restoring them finds their status
+	for _, status := range current {
+		if kept[status.ID] {
+			continue
+		}
+
+		_, err := tx.Exec(`UPDATE tasks SET status_id = (`+initialStatus+`) WHERE status_id = ?`, projectID, status.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to move tasks out of status %q: %w", status.Name, err)
+		}
+		if _, err := tx.Exec(`DELETE FROM statuses WHERE id = ?`, status.ID); err != nil {
+			return nil, fmt.Errorf("failed to remove status %q: %w", status.Name, err)
+		}
+	}
+
+	// A status may have become done or open. Recurring tasks it completes
+	// repeat like when they are completed one by one.
+	recurring, err := queryTasks(tx, `
+		WHERE project_id = ? AND deleted_at IS NULL AND completed = 0 AND recurrence IS NOT NULL
+		AND (SELECT is_done FROM statuses WHERE id = tasks.status_id)
+	`, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE tasks SET completed = (SELECT is_done FROM statuses WHERE id = tasks.status_id)
+		WHERE project_id = ? AND completed != (SELECT is_done FROM statuses WHERE id = tasks.status_id)
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tasks: %w", err)
+	}
+	for _, task := range recurring {
+		if _, err := recurTask(tx, task.ID, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM status_transitions
+		WHERE from_status_id IN (SELECT id FROM statuses WHERE project_id = ?)
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update transitions: %w", err)
+	}
+	for from, targets := range transitions {
+		for _, to := range targets {
+			err := addTransition(tx, ids[strings.ToLower(strings.TrimSpace(from))], ids[strings.ToLower(strings.TrimSpace(to))])
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetWorkflow(projectID)
+}
+
+// initialStatus selects a project's default status, or its first done
+// status for a completed task, for the task in the enclosing query on tasks
+const initialStatus = `
+	SELECT id FROM statuses
+	WHERE project_id = ? AND CASE WHEN tasks.completed THEN is_done ELSE is_default END
+	ORDER BY position LIMIT 1
+`
+
+// createWorkflow adds the statuses of a new project, returning their IDs in
+// order. Transitions are added separately.
+func createWorkflow(tx *sql.Tx, projectID int64, workflow models.Workflow) ([]int64, error) {
+	if err := workflow.Validate(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(workflow))
+	for i, status := range workflow {
+		err := tx.QueryRow(`
+			INSERT INTO statuses (project_id, name, position, is_done, is_default)
+			VALUES (?, ?, ?, ?, ?)
+			RETURNING id
+		`, projectID, strings.TrimSpace(status.Name), i+1, status.Done, status.Default).Scan(&ids[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to create status %q: %w", status.Name, err)
+		}
+	}
+	return ids, nil
+}
+
+// addTransition allows tasks to move from one status to another
+func addTransition(tx *sql.Tx, fromID, toID int64) error {
+	_, err := tx.Exec(`
+		INSERT OR IGNORE INTO status_transitions (from_status_id, to_status_id)
+		VALUES (?, ?)
+	`, fromID, toID)
+	if err != nil {
+		return fmt.Errorf("failed to add transition: %w", err)
+	}
+	return nil
+}
+
+// checkTransition fails when the workflow does not allow moving a task
+// from one status to the other
+func checkTransition(tx *sql.Tx, fromID, toID int64) error {
+	var allowed bool
+	var from, to string
+	err := tx.QueryRow(`
+		SELECT
+			NOT EXISTS (SELECT 1 FROM status_transitions WHERE from_status_id = ?)
+			OR EXISTS (SELECT 1 FROM status_transitions WHERE from_status_id = ? AND to_status_id = ?),
+			(SELECT name FROM statuses WHERE id = ?),
+			(SELECT name FROM statuses WHERE id = ?)
+	`, fromID, fromID, toID, fromID, toID).Scan(&allowed, &from, &to)
+	if err != nil {
+		return fmt.Errorf("failed to check transition: %w", err)
+	}
+	if !allowed {
+		return fmt.Errorf("cannot move task from %q to %q", from, to)
+	}
+	return nil
+}
+
+// statusFor returns the status a task of the project starts in: the first
+// done status when it is completed and the default one otherwise. It is nil
+// when the project has no workflow.
+func statusFor(tx *sql.Tx, projectID int64, completed bool) (*int64, error) {
+	var id int64
+	err := tx.QueryRow(`
+		SELECT id FROM statuses
+		WHERE project_id = ? AND CASE WHEN ? THEN is_done ELSE is_default END
+		ORDER BY position LIMIT 1
+	`, projectID, completed).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+	return &id, nil
+}
+
+// queryStatuses reads the statuses matching the where clause, in workflow
+// order, with the statuses each one allows moving to
+func queryStatuses(q queryer, where string, args ...any) (models.Workflow, error) {
+	rows, err := q.Query(`
+		SELECT id, project_id, name, position, is_done, is_default
+		FROM statuses
+		`+where+`
+		ORDER BY project_id, position, id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statuses: %w", err)
+	}
+	defer rows.Close()
+
+	workflow := models.Workflow{}
+	index := make(map[int64]int)
+	for rows.Next() {
+		var status models.Status
+		err := rows.Scan(&status.ID, &status.ProjectID, &status.Name, &status.Position, &status.Done, &status.Default)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan status: %w", err)
+		}
+		status.Next = []int64{}
+		index[status.ID] = len(workflow)
+		workflow = append(workflow, status)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	transitions, err := q.Query(`
+		SELECT t.from_status_id, t.to_status_id
+		FROM status_transitions t
+		JOIN statuses target ON target.id = t.to_status_id
+		WHERE t.from_status_id IN (SELECT id FROM statuses `+where+`)
+		ORDER BY target.position
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transitions: %w", err)
+	}
+	defer transitions.Close()
+
+	for transitions.Next() {
+		var fromID, toID int64
+		if err := transitions.Scan(&fromID, &toID); err != nil {
+			return nil, fmt.Errorf("failed to scan transition: %w", err)
+		}
+		if i, ok := index[fromID]; ok {
+			workflow[i].Next = append(workflow[i].Next, toID)
+		}
+	}
+
+	return workflow, transitions.Err()
+}
@@ -4,8 +4,26 @@ import (
 	"database/sql"
 	"fmt"
 	"palco/internal/database/models"
+	"palco/internal/recurrence"
+	"sort"
+	"time"
 )
 
+// dateLayout is how scheduled_for is stored; due_at and start_at use
+// timestampLayout
+const dateLayout = "2006-01-02"
+
+// positionGap spaces out the positions of sibling tasks, leaving room to
+// move a task between two others
+const positionGap = 1024
+
+// taskOrder lists tasks by position in projects ordered by hand and by
+// priority otherwise
+const taskOrder = `
+	ORDER BY CASE WHEN (SELECT manual_order FROM projects WHERE projects.id = tasks.project_id) THEN position END,
+	priority DESC, created_at DESC
+`
+
 type TaskRepository struct {
 	db *sql.DB
 }
@@ -14,29 +32,53 @@ func NewTaskRepository(db *sql.DB) *TaskRepository {
 	return &TaskRepository{db: db}
 }
 
-// Create creates a new task and optionally a description note
-func (r *TaskRepository) Create(projectID int64, parentTaskID *int64, title string, description *string, priority int) (*models.Task, error) {
+// Create creates a new task and optionally a description note. repeat is
+// the task's repeat rule, nil when it does not recur.
+func (r *TaskRepository) Create(projectID int64, parentTaskID *int64, title string, description *string, priority int, dates models.TaskDates, repeat *string) (*models.Task, error) {
 	tx, err := r.db.Begin()
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
+	position, err := nextPosition(tx, projectID, parentTaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	statusID, err := statusFor(tx, projectID, false)
+	if err != nil {
+		return nil, err
+	}
+
 	// Insert task
 	taskQuery := `
-		INSERT INTO tasks (project_id, parent_task_id, title, priority)
-		VALUES (?, ?, ?, ?)
-		RETURNING id, project_id, parent_task_id, title, priority, completed, created_at, updated_at
+		INSERT INTO tasks (project_id, parent_task_id, title, priority, due_at, start_at, scheduled_for, recurrence, position, status_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id, project_id, parent_task_id, title, priority, completed, due_at, start_at, scheduled_for, recurrence, position, status_id, created_at, updated_at
 	`
 
 	var task models.Task
-	err = tx.QueryRow(taskQuery, projectID, parentTaskID, title, priority).Scan(
+	err = tx.QueryRow(taskQuery, projectID, parentTaskID, title, priority,
+		dateValue(dates.DueAt, timestampLayout),
+		dateValue(dates.StartAt, timestampLayout),
+		dateValue(dates.ScheduledFor, dateLayout),
+		repeat,
+		position,
+		statusID,
+	).Scan(
 		&task.ID,
 		&task.ProjectID,
 		&task.ParentTaskID,
 		&task.Title,
 		&task.Priority,
 		&task.Completed,
+		&task.DueAt,
+		&task.StartAt,
+		&task.ScheduledFor,
+		&task.Recurrence,
+		&task.Position,
+		&task.StatusID,
 		&task.CreatedAt,
 		&task.UpdatedAt,
 	)
@@ -66,9 +108,9 @@ func (r *TaskRepository) Create(projectID int64, parentTaskID *int64, title stri
 // GetByID retrieves a task by ID
 func (r *TaskRepository) GetByID(id int64) (*models.Task, error) {
 	query := `
-		SELECT id, project_id, parent_task_id, title, priority, completed, created_at, updated_at
+		SELECT id, project_id, parent_task_id, title, priority, completed, due_at, start_at, scheduled_for, recurrence, position, status_id, created_at, updated_at
 		FROM tasks
-		WHERE id = ?
+		WHERE id = ? AND deleted_at IS NULL
 	`
 
 	var task models.Task
@@ -79,6 +121,12 @@ func (r *TaskRepository) GetByID(id int64) (*models.Task, error) {
 		&task.Title,
 		&task.Priority,
 		&task.Completed,
+		&task.DueAt,
+		&task.StartAt,
+		&task.ScheduledFor,
+		&task.Recurrence,
+		&task.Position,
+		&task.StatusID,
 		&task.CreatedAt,
 		&task.UpdatedAt,
 	)
@@ -89,14 +137,14 @@ func (r *TaskRepository) GetByID(id int64) (*models.Task, error) {
 	return &task, nil
 }
 
-// GetByProjectID retrieves all tasks for a project
+// GetByProjectID retrieves all tasks for a project, in the project's task
+// order
 func (r *TaskRepository) GetByProjectID(projectID int64) ([]models.Task, error) {
 	query := `
-		SELECT id, project_id, parent_task_id, title, priority, completed, created_at, updated_at
+		SELECT id, project_id, parent_task_id, title, priority, completed, due_at, start_at, scheduled_for, recurrence, position, status_id, created_at, updated_at
 		FROM tasks
-		WHERE project_id = ?
-		ORDER BY priority DESC, created_at DESC
-	`
+		WHERE project_id = ? AND deleted_at IS NULL
+	` + taskOrder
 
 	rows, err := r.db.Query(query, projectID)
 	if err != nil {
@@ -114,6 +162,12 @@ func (r *TaskRepository) GetByProjectID(projectID int64) ([]models.Task, error)
 			&task.Title,
 			&task.Priority,
 			&task.Completed,
+			&task.DueAt,
+			&task.StartAt,
+			&task.ScheduledFor,
+			&task.Recurrence,
+			&task.Position,
+			&task.StatusID,
 			&task.CreatedAt,
 			&task.UpdatedAt,
 		)
@@ -129,11 +183,10 @@ func (r *TaskRepository) GetByProjectID(projectID int64) ([]models.Task, error)
 // GetSubtasks retrieves all subtasks for a parent task
 func (r *TaskRepository) GetSubtasks(parentTaskID int64) ([]models.Task, error) {
 	query := `
-		SELECT id, project_id, parent_task_id, title, priority, completed, created_at, updated_at
+		SELECT id, project_id, parent_task_id, title, priority, completed, due_at, start_at, scheduled_for, recurrence, position, status_id, created_at, updated_at
 		FROM tasks
-		WHERE parent_task_id = ?
-		ORDER BY priority DESC, created_at DESC
-	`
+		WHERE parent_task_id = ? AND deleted_at IS NULL
+	` + taskOrder
 
 	rows, err := r.db.Query(query, parentTaskID)
 	if err != nil {
@@ -151,6 +204,12 @@ func (r *TaskRepository) GetSubtasks(parentTaskID int64) ([]models.Task, error)
 			&task.Title,
 			&task.Priority,
 			&task.Completed,
+			&task.DueAt,
+			&task.StartAt,
+			&task.ScheduledFor,
+			&task.Recurrence,
+			&task.Position,
+			&task.StatusID,
 			&task.CreatedAt,
 			&task.UpdatedAt,
 		)
@@ -163,23 +222,36 @@ func (r *TaskRepository) GetSubtasks(parentTaskID int64) ([]models.Task, error)
 	return tasks, nil
 }
 
-// Update updates a task
-func (r *TaskRepository) Update(id int64, title string, priority int, completed bool) (*models.Task, error) {
+// Update edits a task's fields. Completing and reopening it go through
+// SetCompleted and SetStatus, which also handle its recurrence.
+func (r *TaskRepository) Update(id int64, title string, priority int, dates models.TaskDates, repeat *string) (*models.Task, error) {
 	query := `
 		UPDATE tasks
-		SET title = ?, priority = ?, completed = ?
-		WHERE id = ?
-		RETURNING id, project_id, parent_task_id, title, priority, completed, created_at, updated_at
+		SET title = ?, priority = ?, due_at = ?, start_at = ?, scheduled_for = ?, recurrence = ?
+		WHERE id = ? AND deleted_at IS NULL
+		RETURNING id, project_id, parent_task_id, title, priority, completed, due_at, start_at, scheduled_for, recurrence, position, status_id, created_at, updated_at
 	`
 
 	var task models.Task
-	err := r.db.QueryRow(query, title, priority, completed, id).Scan(
+	err := r.db.QueryRow(query, title, priority,
+		dateValue(dates.DueAt, timestampLayout),
+		dateValue(dates.StartAt, timestampLayout),
+		dateValue(dates.ScheduledFor, dateLayout),
+		repeat,
+		id,
+	).Scan(
 		&task.ID,
 		&task.ProjectID,
 		&task.ParentTaskID,
 		&task.Title,
 		&task.Priority,
 		&task.Completed,
+		&task.DueAt,
+		&task.StartAt,
+		&task.ScheduledFor,
+		&task.Recurrence,
+		&task.Position,
+		&task.StatusID,
 		&task.CreatedAt,
 		&task.UpdatedAt,
 	)
@@ -190,23 +262,526 @@ func (r *TaskRepository) Update(id int64, title string, priority int, completed
 	return &task, nil
 }
 
-// Delete deletes a task
+// Completion is a task after completing or reopening it, with what the
+// completion set off
+type Completion struct {
+	Task      *models.Task
+	Next      *models.Task  // Next occurrence of a recurring task, nil otherwise
+	Unblocked []models.Task // Open tasks the completion left without open blockers
+}
+
+// SetCompleted completes or reopens a task, moving it to the first done
+// status or the default one when its workflow allows that. Completing a
+// recurring task creates its next occurrence in the same transaction, with
+// dates resolved against now (see recurTask).
+func (r *TaskRepository) SetCompleted(id int64, completed bool, now time.Time) (*Completion, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var projectID int64
+	var wasCompleted bool
+	var statusID sql.NullInt64
+	err = tx.QueryRow(`
+		SELECT project_id, completed, status_id FROM tasks
+		WHERE id = ? AND deleted_at IS NULL
+	`, id).Scan(&projectID, &wasCompleted, &statusID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("task #%d not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	if completed != wasCompleted {
+		next, err := statusFor(tx, projectID, completed)
+		if err != nil {
+			return nil, err
+		}
+		if next != nil {
+			if statusID.Valid {
+				if err := checkTransition(tx, statusID.Int64, *next); err != nil {
+					return nil, err
+				}
+			}
+			statusID = sql.NullInt64{Int64: *next, Valid: true}
+		}
+	}
+
+	_, err = tx.Exec(`UPDATE tasks SET completed = ?, status_id = ? WHERE id = ?`, completed, statusID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update task: %w", err)
+	}
+
+	return r.finishCompletion(tx, id, completed && !wasCompleted, now)
+}
+
+// SetStatus moves a task to a status of its project's workflow. The task is
+// completed when the status is a done one and reopened otherwise; completing
+// it works like SetCompleted.
+func (r *TaskRepository) SetStatus(id, statusID int64, now time.Time) (*Completion, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var projectID int64
+	var wasCompleted bool
+	var current sql.NullInt64
+	err = tx.QueryRow(`
+		SELECT project_id, completed, status_id FROM tasks
+		WHERE id = ? AND deleted_at IS NULL
+	`, id).Scan(&projectID, &wasCompleted, &current)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("task #%d not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	var statusProjectID int64
+	var done bool
+	err = tx.QueryRow(`SELECT project_id, is_done FROM statuses WHERE id = ?`, statusID).Scan(&statusProjectID, &done)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+	if err == sql.ErrNoRows || statusProjectID != projectID {
+		return nil, fmt.Errorf("status #%d is not in the task's workflow", statusID)
+	}
+
+	if current.Valid && current.Int64 != statusID {
+		if err := checkTransition(tx, current.Int64, statusID); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec(`UPDATE tasks SET status_id = ?, completed = ? WHERE id = ?`, statusID, done, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update task status: %w", err)
+	}
+
+	return r.finishCompletion(tx, id, done && !wasCompleted, now)
+}
+
+// finishCompletion creates the next occurrence of a task that was just
+// completed and finds the tasks it unblocked, then commits tx
+func (r *TaskRepository) finishCompletion(tx *sql.Tx, id int64, completed bool, now time.Time) (*Completion, error) {
+	var nextID int64
+	var unblocked []models.Task
+	if completed {
+		var err error
+		if nextID, err = recurTask(tx, id, now); err != nil {
+			return nil, err
+		}
+		if unblocked, err = queryUnblockedBy(tx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	task, err := r.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	completion := &Completion{Task: task, Unblocked: unblocked}
+	if nextID != 0 {
+		if completion.Next, err = r.GetByID(nextID); err != nil {
+			return nil, err
+		}
+	}
+	return completion, nil
+}
+
+// Delete moves a task with its subtasks and notes to the trash
 func (r *TaskRepository) Delete(id int64) error {
-	query := `DELETE FROM tasks WHERE id = ?`
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	result, err := r.db.Exec(query, id)
+	var exists bool
+	err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ? AND deleted_at IS NULL)`, id).Scan(&exists)
 	if err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
+	if !exists {
+		return fmt.Errorf("task not found")
+	}
+
+	deletedAt := trashTimestamp()
 
-	rowsAffected, err := result.RowsAffected()
+	_, err = tx.Exec(`
+		UPDATE notes SET deleted_at = ?
+		WHERE deleted_at IS NULL AND task_id IN (`+taskSubtreeIDs+`)
+	`, deletedAt, id)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return fmt.Errorf("failed to delete task notes: %w", err)
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("task not found")
+	_, err = tx.Exec(`
+		UPDATE tasks SET deleted_at = ?
+		WHERE deleted_at IS NULL AND id IN (`+taskSubtreeIDs+`)
+	`, deletedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
 }
+
+// Move moves a task with its subtasks under parentID, or to the top level
+// of projectID when parentID is nil. The parent has to be in projectID and
+// cannot be the task itself or one of its subtasks.
+func (r *TaskRepository) Move(id, projectID int64, parentID *int64) (*models.Task, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	found, err := queryTasks(tx, "WHERE id = ? AND deleted_at IS NULL", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("task #%d not found", id)
+	}
+	task := found[0]
+
+	// Moving a task where it already is keeps its place among its siblings
+	if task.ProjectID.Int64 == projectID && sameParent(task.ParentTaskID, parentID) {
+		return &task, nil
+	}
+
+	var exists bool
+	err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM projects WHERE id = ? AND deleted_at IS NULL)`, projectID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move task: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("project #%d not found", projectID)
+	}
+
+	if parentID != nil {
+		var parentProjectID int64
+		err = tx.QueryRow(`SELECT project_id FROM tasks WHERE id = ? AND deleted_at IS NULL`, *parentID).Scan(&parentProjectID)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("task #%d not found", *parentID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to move task: %w", err)
+		}
+		if parentProjectID != projectID {
+			return nil, fmt.Errorf("task #%d is in another project", *parentID)
+		}
+
+		// The subtree includes the task itself
+		var descendant bool
+		err = tx.QueryRow(`SELECT ? IN (`+taskSubtreeIDs+`)`, *parentID, id).Scan(&descendant)
+		if err != nil {
+			return nil, fmt.Errorf("failed to move task: %w", err)
+		}
+		if descendant {
+			return nil, fmt.Errorf("cannot move task #%d under itself or one of its subtasks", id)
+		}
+	}
+
+	// The task goes last among its new siblings
+	position, err := nextPosition(tx, projectID, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Changing position skips the timestamp trigger, which is meant for
+	// reordering, so the move sets updated_at itself
+	_, err = tx.Exec(`
+		UPDATE tasks SET parent_task_id = ?, position = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, parentID, position, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move task: %w", err)
+	}
+
+	// Subtasks in the trash move too, so restoring them keeps them with
+	// their parent. Tasks keep their status when the new project's workflow
+	// has one by the same name, and start over in it otherwise.
+	_, err = tx.Exec(`
+		UPDATE tasks SET project_id = ?, status_id = COALESCE((
+			SELECT target.id FROM statuses target
+			JOIN statuses current ON current.id = tasks.status_id
+			WHERE target.project_id = ? AND target.name = current.name COLLATE NOCASE
+			AND target.is_done = current.is_done
+		), (`+initialStatus+`))
+		WHERE id IN (`+taskSubtreeIDs+`) AND project_id != ?
+	`, projectID, projectID, projectID, id, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move subtasks: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByID(id)
+}
+
+// Reorder moves a task offset places among its siblings, towards the top
+// when offset is negative. The task takes a position between its new
+// neighbours; the siblings are spaced out again when there is no room left.
+func (r *TaskRepository) Reorder(id int64, offset int) (*models.Task, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	found, err := queryTasks(tx, "WHERE id = ? AND deleted_at IS NULL", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("task #%d not found", id)
+	}
+	task := found[0]
+
+	siblings, err := queryTasks(tx, `
+		WHERE project_id = ? AND parent_task_id IS ? AND deleted_at IS NULL AND id != ?
+	`, task.ProjectID, task.ParentTaskID, id)
+	if err != nil {
+		return nil, err
+	}
+	sortByPosition(siblings)
+
+	from := sort.Search(len(siblings), func(i int) bool {
+		return siblings[i].Position > task.Position ||
+			siblings[i].Position == task.Position && siblings[i].ID > task.ID
+	})
+	to := min(max(from+offset, 0), len(siblings))
+	if to == from {
+		return &task, nil
+	}
+
+	var position int64
+	switch {
+	case to == 0:
+		position = siblings[0].Position - positionGap
+	case to == len(siblings):
+		position = siblings[to-1].Position + positionGap
+	case siblings[to].Position-siblings[to-1].Position > 1:
+		position = siblings[to-1].Position + (siblings[to].Position-siblings[to-1].Position)/2
+	default:
+		// No room between the neighbours, so space out the whole group
+		ordered := append(append(append([]models.Task{}, siblings[:to]...), task), siblings[to:]...)
+		for i, sibling := range ordered {
+			_, err := tx.Exec(`UPDATE tasks SET position = ? WHERE id = ?`, int64(i+1)*positionGap, sibling.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to reorder tasks: %w", err)
+			}
+		}
+		position = int64(to+1) * positionGap
+	}
+
+	_, err = tx.Exec(`UPDATE tasks SET position = ? WHERE id = ?`, position, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reorder task: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByID(id)
+}
+
+// recurTask creates the next occurrence of a completed recurring task, with
+// its description note and open copies of its subtasks, returning its ID,
+// or 0 when the task does not recur. Its dates move to the rule's next
+// occurrence after the due date (or, without one, the scheduled or start
+// date), or after now for rules repeating after completion; occurrences
+// missed while the task was overdue are skipped. A task without dates gets
+// the next occurrence as its due date.
+//
+// The rule moves to the new task, so completing the old one again does not
+// repeat it twice.
+func recurTask(tx *sql.Tx, id int64, now time.Time) (int64, error) {
+	tasks, err := queryTasks(tx, "WHERE id = ? AND deleted_at IS NULL", id)
+	if err != nil {
+		return 0, err
+	}
+	if len(tasks) == 0 {
+		return 0, fmt.Errorf("task #%d not found", id)
+	}
+	task := tasks[0]
+	if !task.Recurrence.Valid {
+		return 0, nil
+	}
+
+	rule, err := recurrence.Parse(task.Recurrence.String)
+	if err != nil {
+		return 0, fmt.Errorf("task %q: %w", task.Title, err)
+	}
+
+	// Planning dates are wall-clock times read back as UTC, so today is
+	// built the same way
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	dates := task.Dates()
+
+	from := today
+	switch {
+	case dates.DueAt != nil:
+		from = *dates.DueAt
+	case dates.ScheduledFor != nil:
+		from = *dates.ScheduledFor
+	case dates.StartAt != nil:
+		from = *dates.StartAt
+	}
+	if rule.AfterCompletion {
+		from = time.Date(today.Year(), today.Month(), today.Day(), from.Hour(), from.Minute(), 0, 0, time.UTC)
+	}
+
+	// The first occurrence pins the day of the month for the whole series
+	rule = rule.Anchored(from)
+	repeat := rule.String()
+
+	next := rule.NextAfter(from, today)
+	nextDates := shiftDates(dates, models.DaysUntil(next, from))
+	if dates.DueAt == nil && dates.ScheduledFor == nil && dates.StartAt == nil {
+		nextDates.DueAt = &next
+	}
+
+	var parentID *int64
+	if task.ParentTaskID.Valid {
+		parentID = &task.ParentTaskID.Int64
+	}
+
+	nextID, err := copyTask(tx, task, parentID, nextDates, &repeat, models.DaysUntil(next, from))
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(`UPDATE tasks SET recurrence = NULL WHERE id = ?`, id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update task: %w", err)
+	}
+
+	return nextID, nil
+}
+
+// copyTask inserts an open copy of task in the default status, last under
+// parentID, with the given dates and rule, then copies its description note
+// and, recursively, its subtasks with their dates moved by days
+func copyTask(tx *sql.Tx, task models.Task, parentID *int64, dates models.TaskDates, repeat *string, days int) (int64, error) {
+	position, err := nextPosition(tx, task.ProjectID.Int64, parentID)
+	if err != nil {
+		return 0, err
+	}
+
+	statusID, err := statusFor(tx, task.ProjectID.Int64, false)
+	if err != nil {
+		return 0, err
+	}
+
+	var id int64
+	err = tx.QueryRow(`
+		INSERT INTO tasks (project_id, parent_task_id, title, priority, due_at, start_at, scheduled_for, recurrence, position, status_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`, task.ProjectID, parentID, task.Title, task.Priority,
+		dateValue(dates.DueAt, timestampLayout),
+		dateValue(dates.StartAt, timestampLayout),
+		dateValue(dates.ScheduledFor, dateLayout),
+		repeat,
+		position,
+		statusID,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy task %q: %w", task.Title, err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO notes (task_id, content, is_description)
+		SELECT ?, content, 1 FROM notes
+		WHERE task_id = ? AND is_description = 1 AND deleted_at IS NULL
+	`, id, task.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy description note: %w", err)
+	}
+
+	subtasks, err := queryTasks(tx, "WHERE parent_task_id = ? AND deleted_at IS NULL", task.ID)
+	if err != nil {
+		return 0, err
+	}
+	sortByPosition(subtasks)
+	for _, subtask := range subtasks {
+		// Subtasks keep their own rules; copying them would repeat them twice
+		_, err := copyTask(tx, subtask, &id, shiftDates(subtask.Dates(), days), nil, days)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return id, nil
+}
+
+// shiftDates moves each planning date by days, keeping its time of day
+func shiftDates(dates models.TaskDates, days int) models.TaskDates {
+	for _, date := range []**time.Time{&dates.DueAt, &dates.StartAt, &dates.ScheduledFor} {
+		if *date != nil {
+			shifted := (*date).AddDate(0, 0, days)
+			*date = &shifted
+		}
+	}
+	return dates
+}
+
+// nextPosition returns the position after the last task under parentID in
+// projectID, counting trashed tasks so restoring them keeps the order
+func nextPosition(tx *sql.Tx, projectID int64, parentID *int64) (int64, error) {
+	var position int64
+	err := tx.QueryRow(`
+		SELECT COALESCE(MAX(position), 0) + ? FROM tasks
+		WHERE project_id = ? AND parent_task_id IS ?
+	`, positionGap, projectID, parentID).Scan(&position)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get task position: %w", err)
+	}
+	return position, nil
+}
+
+// sortByPosition orders tasks by position, keeping their current order on
+// ties
+func sortByPosition(tasks []models.Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].Position < tasks[j].Position
+	})
+}
+
+// sameParent reports whether a task's parent is parentID, both being nil
+// for a top-level task
+func sameParent(current sql.NullInt64, parentID *int64) bool {
+	if parentID == nil {
+		return !current.Valid
+	}
+	return current.Valid && current.Int64 == *parentID
+}
+
+// dateValue formats a planning date for storage, or nil when it is unset.
+// Dates are wall-clock times, so they are written as they are rather than
+// converted to UTC.
+func dateValue(t *time.Time, layout string) any {
+	if t == nil {
+		return nil
+	}
+	return t.Format(layout)
+}
@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"palco/internal/database/models"
+)
+
+func TestSetCompletedRecurs(t *testing.T) {
+	r := newRepos(t)
+	project := r.project(t, "Home")
+
+	due := time.Date(2027, time.January, 31, 9, 0, 0, 0, time.UTC)
+	repeat := "FREQ=MONTHLY"
+	task, err := r.tasks.Create(project.ID, nil, "Pay rent", nil, models.PriorityNone, models.TaskDates{DueAt: &due}, &repeat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waiting := r.task(t, project.ID, nil, "Balance budget")
+	if err := r.dependencies.Add(waiting.ID, task.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2027, time.January, 20, 12, 0, 0, 0, time.UTC)
+	completion, err := r.tasks.SetCompleted(task.ID, true, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !completion.Task.Completed || completion.Task.Recurrence.Valid {
+		t.Errorf("completed task = %+v, want completed without its rule", completion.Task)
+	}
+	if completion.Next == nil {
+		t.Fatal("no next occurrence")
+	}
+	if got := completion.Next.DueAt.Time; !got.Equal(time.Date(2027, time.February, 28, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("next due = %v, want 2027-02-28 09:00", got)
+	}
+	if completion.Next.Recurrence.String != "FREQ=MONTHLY;BYMONTHDAY=31" {
+		t.Errorf("next rule = %q, want the day of the month pinned", completion.Next.Recurrence.String)
+	}
+	if len(completion.Unblocked) != 1 || completion.Unblocked[0].ID != waiting.ID {
+		t.Errorf("unblocked = %v, want task #%d", completion.Unblocked, waiting.ID)
+	}
+
+	// Completing it again after reopening does not repeat it twice
+	if _, err := r.tasks.SetCompleted(task.ID, false, now); err != nil {
+		t.Fatal(err)
+	}
+	again, err := r.tasks.SetCompleted(task.ID, true, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Next != nil {
+		t.Errorf("completing again created %+v", again.Next)
+	}
+}
+
+func TestSetStatusRecurs(t *testing.T) {
+	r := newRepos(t)
+	project := r.project(t, "Home")
+
+	repeat := "FREQ=WEEKLY"
+	task, err := r.tasks.Create(project.ID, nil, "Water plants", nil, models.PriorityNone, models.TaskDates{}, &repeat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	workflow, err := r.statuses.GetWorkflow(project.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done, _ := workflow.ByName("Done")
+	inProgress, _ := workflow.ByName("In Progress")
+
+	now := time.Date(2027, time.March, 3, 8, 0, 0, 0, time.UTC)
+	completion, err := r.tasks.SetStatus(task.ID, inProgress.ID, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if completion.Next != nil {
+		t.Fatalf("moving to an open status created %+v", completion.Next)
+	}
+
+	completion, err = r.tasks.SetStatus(task.ID, done.ID, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if completion.Next == nil || !completion.Next.DueAt.Time.Equal(time.Date(2027, time.March, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("next = %+v, want due 2027-03-10", completion.Next)
+	}
+}
+
+func TestSetWorkflowRecursCompletedTasks(t *testing.T) {
+	r := newRepos(t)
+	project := r.project(t, "Home")
+
+	repeat := "FREQ=DAILY"
+	task, err := r.tasks.Create(project.ID, nil, "Stretch", nil, models.PriorityNone, models.TaskDates{}, &repeat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The task sits in Todo, which becomes a done status
+	workflow := models.Workflow{{Name: "Later", Default: true}, {Name: "Todo", Done: true}}
+	if _, err := r.statuses.SetWorkflow(project.ID, workflow, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks, err := r.tasks.GetByProjectID(project.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("got %d tasks, want the task and its next occurrence", len(tasks))
+	}
+	for _, got := range tasks {
+		if got.ID == task.ID && (!got.Completed || got.Recurrence.Valid) {
+			t.Errorf("task = %+v, want completed without its rule", got)
+		}
+		if got.ID != task.ID && got.Recurrence.String != repeat {
+			t.Errorf("next occurrence rule = %q, want %q", got.Recurrence.String, repeat)
+		}
+	}
+}
+
+func TestMoveRejectsOwnSubtree(t *testing.T) {
+	r := newRepos(t)
+	project := r.project(t, "Home")
+	other := r.project(t, "Work")
+
+	parent := r.task(t, project.ID, nil, "Paint the house")
+	child := r.task(t, project.ID, &parent.ID, "Buy paint")
+	grandchild := r.task(t, project.ID, &child.ID, "Pick a colour")
+	elsewhere := r.task(t, other.ID, nil, "Write report")
+
+	tests := []struct {
+		name      string
+		projectID int64
+		parentID  int64
+	}{
+		{"itself", project.ID, parent.ID},
+		{"its subtask", project.ID, child.ID},
+		{"a deeper subtask", project.ID, grandchild.ID},
+		{"a task in another project", project.ID, elsewhere.ID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := r.tasks.Move(parent.ID, tt.projectID, &tt.parentID); err == nil {
+				t.Errorf("moving task #%d under #%d succeeded, want an error", parent.ID, tt.parentID)
+			}
+		})
+	}
+
+	got, err := r.tasks.GetByID(parent.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ParentTaskID.Valid {
+		t.Errorf("parent = %v, want the task left at the top level", got.ParentTaskID)
+	}
+}
+
+func TestMoveToAnotherProject(t *testing.T) {
+	r := newRepos(t)
+	home := r.project(t, "Home")
+	work := r.project(t, "Work")
+
+	workflow := models.Workflow{{Name: "Backlog", Default: true}, {Name: "In Progress"}, {Name: "Finished", Done: true}}
+	target, err := r.statuses.SetWorkflow(work.ID, workflow, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := r.statuses.GetWorkflow(home.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	existing := r.task(t, work.ID, nil, "Write report")
+	parent := r.task(t, home.ID, nil, "Plan the move")
+	child := r.task(t, home.ID, &parent.ID, "Book a van")
+	grandchild := r.task(t, home.ID, &child.ID, "Compare prices")
+
+	inProgress, _ := current.ByName("In Progress")
+	done, _ := current.ByName("Done")
+	now := time.Date(2027, time.May, 1, 10, 0, 0, 0, time.UTC)
+	for _, step := range []struct{ id, statusID int64 }{
+		{parent.ID, inProgress.ID},
+		{child.ID, inProgress.ID},
+		{child.ID, done.ID},
+	} {
+		if _, err := r.tasks.SetStatus(step.id, step.statusID, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	moved, err := r.tasks.Move(parent.ID, work.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.Position <= existing.Position {
+		t.Errorf("position = %d, want after the project's task at %d", moved.Position, existing.Position)
+	}
+
+	// Statuses carry over by name and start over in the workflow otherwise
+	tests := []struct {
+		id        int64
+		parentID  int64
+		status    string
+		completed bool
+	}{
+		{parent.ID, 0, "In Progress", false},
+		{child.ID, parent.ID, "Finished", true},
+		{grandchild.ID, child.ID, "Backlog", false},
+	}
+
+	for _, tt := range tests {
+		got, err := r.tasks.GetByID(tt.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ProjectID.Int64 != work.ID {
+			t.Errorf("task #%d project = %d, want %d", tt.id, got.ProjectID.Int64, work.ID)
+		}
+		if got.ParentTaskID.Int64 != tt.parentID {
+			t.Errorf("task #%d parent = %d, want %d", tt.id, got.ParentTaskID.Int64, tt.parentID)
+		}
+		if status := target.Of(*got); status.Name != tt.status || got.Completed != tt.completed {
+			t.Errorf("task #%d status = %q (completed %v), want %q (completed %v)", tt.id, status.Name, got.Completed, tt.status, tt.completed)
+		}
+	}
+}
+
+func TestMoveInPlaceKeepsPosition(t *testing.T) {
+	r := newRepos(t)
+	project := r.project(t, "Home")
+
+	first := r.task(t, project.ID, nil, "Sweep")
+	r.task(t, project.ID, nil, "Mop")
+	child := r.task(t, project.ID, &first.ID, "Find the broom")
+
+	for _, task := range []*models.Task{first, child} {
+		var parentID *int64
+		if task.ParentTaskID.Valid {
+			parentID = &task.ParentTaskID.Int64
+		}
+		got, err := r.tasks.Move(task.ID, project.ID, parentID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Position != task.Position {
+			t.Errorf("task #%d position = %d, want %d", task.ID, got.Position, task.Position)
+		}
+	}
+}
+
+func TestReorderSpacesOutSiblings(t *testing.T) {
+	r := newRepos(t)
+	project := r.project(t, "Home")
+
+	var want []int64
+	for _, title := range []string{"Wash", "Dry", "Fold"} {
+		want = append(want, r.task(t, project.ID, nil, title).ID)
+	}
+
+	// Moving the last task up one place halves the gap between the first
+	// two each time, until there is no room left
+	for range 2 * 12 {
+		last := want[len(want)-1]
+		if _, err := r.tasks.Reorder(last, -1); err != nil {
+			t.Fatal(err)
+		}
+		want = append([]int64{want[0], last}, want[1:len(want)-1]...)
+
+		tasks, err := r.tasks.GetByProjectID(project.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sortByPosition(tasks)
+
+		var got []int64
+		for i, task := range tasks {
+			got = append(got, task.ID)
+			if i > 0 && task.Position == tasks[i-1].Position {
+				t.Fatalf("tasks #%d and #%d share position %d", tasks[i-1].ID, task.ID, task.Position)
+			}
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("order = %v, want %v", got, want)
+		}
+	}
+}
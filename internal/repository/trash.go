@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"palco/internal/database/models"
+	"time"
+)
+
+// trashLayout is how deleted_at is written. Milliseconds keep deletions
+// made in the same second apart, because rows deleted together share one
+// deleted_at and are restored together.
+const trashLayout = "2006-01-02 15:04:05.000"
+
+// taskSubtreeIDs selects the ID of a task and of all its subtasks, at any
+// depth. It takes the root task ID as its only argument.
+const taskSubtreeIDs = `
+	WITH RECURSIVE subtree(id) AS (
+		SELECT ?
+		UNION ALL
+		SELECT tasks.id FROM tasks JOIN subtree ON tasks.parent_task_id = subtree.id
+	)
+	SELECT id FROM subtree
+`
+
+// trashTimestamp returns the deleted_at for rows being deleted now
+func trashTimestamp() string {
+	return time.Now().UTC().Format(trashLayout)
+}
+
+type TrashRepository struct {
+	db *sql.DB
+}
+
+func NewTrashRepository(db *sql.DB) *TrashRepository {
+	return &TrashRepository{db: db}
+}
+
+// PurgeResult reports how many rows a purge removed for good
+type PurgeResult struct {
+	Projects int
+	Tasks    int
+	Notes    int
+}
+
+// GetAll lists the items that were deleted on their own, newest first.
+// Tasks and notes deleted along with their project or parent task are
+// only counted in that item.
+func (r *TrashRepository) GetAll() ([]models.TrashItem, error) {
+	query := `
+		SELECT 'project', p.id, p.name, '', p.deleted_at,
+			(SELECT COUNT(*) FROM tasks WHERE project_id = p.id AND deleted_at = p.deleted_at),
+			(SELECT COUNT(*) FROM notes WHERE deleted_at = p.deleted_at
+				AND (project_id = p.id OR task_id IN (SELECT id FROM tasks WHERE project_id = p.id)))
+		FROM projects p
+		WHERE p.deleted_at IS NOT NULL
+
+		UNION ALL
+
+		SELECT 'task', t.id, t.title, p.name, t.deleted_at, 0, 0
+		FROM tasks t
+		JOIN projects p ON p.id = t.project_id
+		LEFT JOIN tasks parent ON parent.id = t.parent_task_id
+		WHERE t.deleted_at IS NOT NULL
+		AND p.deleted_at IS NOT t.deleted_at
+		AND (parent.id IS NULL OR parent.deleted_at IS NOT t.deleted_at)
+
+		UNION ALL
+
+		SELECT 'note', n.id, n.content, COALESCE(p.name, tp.name), n.deleted_at, 0, 0
+		FROM notes n
+		LEFT JOIN projects p ON p.id = n.project_id
+		LEFT JOIN tasks t ON t.id = n.task_id
+		LEFT JOIN projects tp ON tp.id = t.project_id
+		WHERE n.deleted_at IS NOT NULL
+		AND COALESCE(p.deleted_at, t.deleted_at) IS NOT n.deleted_at
+
+		ORDER BY 5 DESC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trash: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.TrashItem
+	for rows.Next() {
+		var item models.TrashItem
+		err := rows.Scan(
+			&item.Kind,
+			&item.ID,
+			&item.Title,
+			&item.Project,
+			&item.DeletedAt,
+			&item.Tasks,
+			&item.Notes,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trash item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get trash: %w", err)
+	}
+
+	// Subtasks and notes of a deleted task are counted here rather than
+	// in the query, which would need a recursive subquery per row
+	for i, item := range items {
+		if item.Kind != models.TrashTask {
+			continue
+		}
+
+		err := r.db.QueryRow(`
+			SELECT
+				(SELECT COUNT(*) FROM tasks WHERE id IN (`+taskSubtreeIDs+`) AND id != ? AND deleted_at = t.deleted_at),
+				(SELECT COUNT(*) FROM notes WHERE task_id IN (`+taskSubtreeIDs+`) AND deleted_at = t.deleted_at)
+			FROM tasks t
+			WHERE t.id = ?
+		`, item.ID, item.ID, item.ID, item.ID).Scan(&items[i].Tasks, &items[i].Notes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count deleted subtasks: %w", err)
+		}
+	}
+
+	return items, nil
+}
+
+// Restore takes an item out of the trash together with everything that
+// was deleted with it. A task or note can only be restored while its
+// project and parent task are not in the trash.
+func (r *TrashRepository) Restore(kind string, id int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The root goes last: the other statements look up its deleted_at
+	type statement struct {
+		query string
+		args  []any
+	}
+	var statements []statement
+	switch kind {
+	case models.TrashProject:
+		statements = []statement{
+			{`UPDATE notes SET deleted_at = NULL
+			WHERE deleted_at = (SELECT deleted_at FROM projects WHERE id = ?)
+			AND (project_id = ? OR task_id IN (SELECT id FROM tasks WHERE project_id = ?))`, []any{id, id, id}},
+			{`UPDATE tasks SET deleted_at = NULL
+			WHERE project_id = ? AND deleted_at = (SELECT deleted_at FROM projects WHERE id = ?)`, []any{id, id}},
+			{`UPDATE projects SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`, []any{id}},
+		}
+	case models.TrashTask:
+		if err := checkRestorable(tx, `
+			SELECT p.deleted_at IS NOT NULL, COALESCE(parent.deleted_at IS NOT NULL, 0)
+			FROM tasks t
+			JOIN projects p ON p.id = t.project_id
+			LEFT JOIN tasks parent ON parent.id = t.parent_task_id
+			WHERE t.id = ? AND t.deleted_at IS NOT NULL
+		`, kind, id); err != nil {
+			return err
+		}
+		statements = []statement{
+			{`UPDATE notes SET deleted_at = NULL
+			WHERE deleted_at = (SELECT deleted_at FROM tasks WHERE id = ?)
+			AND task_id IN (` + taskSubtreeIDs + `)`, []any{id, id}},
+			{`UPDATE tasks SET deleted_at = NULL
+			WHERE deleted_at = (SELECT deleted_at FROM tasks WHERE id = ?)
+			AND id IN (` + taskSubtreeIDs + `) AND id != ?`, []any{id, id, id}},
+			{`UPDATE tasks SET deleted_at = NULL WHERE id = ?`, []any{id}},
+		}
+	case models.TrashNote:
+		if err := checkRestorable(tx, `
+			SELECT COALESCE(p.deleted_at, tp.deleted_at) IS NOT NULL, COALESCE(t.deleted_at IS NOT NULL, 0)
+			FROM notes n
+			LEFT JOIN projects p ON p.id = n.project_id
+			LEFT JOIN tasks t ON t.id = n.task_id
+			LEFT JOIN projects tp ON tp.id = t.project_id
+			WHERE n.id = ? AND n.deleted_at IS NOT NULL
+		`, kind, id); err != nil {
+			return err
+		}
+		statements = []statement{{`UPDATE notes SET deleted_at = NULL WHERE id = ?`, []any{id}}}
+	default:
+		return fmt.Errorf("unknown trash item kind %q", kind)
+	}
+
+	var rowsAffected int64
+	for _, stmt := range statements {
+		result, err := tx.Exec(stmt.query, stmt.args...)
+		if err != nil {
+			return fmt.Errorf("failed to restore %s: %w", kind, err)
+		}
+		rowsAffected, err = result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s not found in trash", kind)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// checkRestorable runs query, which reports whether the item's project and
+// parent task are trashed, and fails when either is
+func checkRestorable(tx *sql.Tx, query, kind string, id int64) error {
+	var projectTrashed, parentTrashed bool
+	err := tx.QueryRow(query, id).Scan(&projectTrashed, &parentTrashed)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%s not found in trash", kind)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore %s: %w", kind, err)
+	}
+
+	switch {
+	case projectTrashed:
+		return fmt.Errorf("the %s's project is in the trash, restore it first", kind)
+	case parentTrashed:
+		return fmt.Errorf("the %s's task is in the trash, restore it first", kind)
+	}
+	return nil
+}
+
+// Delete permanently deletes an item in the trash and everything that
+// was deleted with it
+func (r *TrashRepository) Delete(kind string, id int64) error {
+	var query string
+	switch kind {
+	case models.TrashProject:
+		query = `DELETE FROM projects WHERE id = ? AND deleted_at IS NOT NULL`
+	case models.TrashTask:
+		query = `DELETE FROM tasks WHERE id = ? AND deleted_at IS NOT NULL`
+	case models.TrashNote:
+		query = `DELETE FROM notes WHERE id = ? AND deleted_at IS NOT NULL`
+	default:
+		return fmt.Errorf("unknown trash item kind %q", kind)
+	}
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s: %w", kind, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s not found in trash", kind)
+	}
+
+	return nil
+}
+
+// Purge permanently deletes everything that was moved to the trash before
+// the given time
+func (r *TrashRepository) Purge(before time.Time) (*PurgeResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	cutoff := before.UTC().Format(trashLayout)
+	result := &PurgeResult{}
+
+	// Rows are counted before deleting because ON DELETE CASCADE removes
+	// some of them without being reported as affected
+	for _, table := range []struct {
+		name  string
+		count *int
+	}{
+		{"projects", &result.Projects},
+		{"tasks", &result.Tasks},
+		{"notes", &result.Notes},
+	} {
+		err := tx.QueryRow(`SELECT COUNT(*) FROM `+table.name+` WHERE deleted_at < ?`, cutoff).Scan(table.count)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count %s to purge: %w", table.name, err)
+		}
+	}
+
+	for _, table := range []string{"notes", "tasks", "projects"} {
+		if _, err := tx.Exec(`DELETE FROM `+table+` WHERE deleted_at < ?`, cutoff); err != nil {
+			return nil, fmt.Errorf("failed to purge %s: %w", table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,560 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"palco/internal/database/models"
+	"palco/internal/recurrence"
+	"sort"
+	"time"
+)
+
+// timestampLayout matches what SQLite's CURRENT_TIMESTAMP writes
+const timestampLayout = "2006-01-02 15:04:05"
+
+type WorkspaceRepository struct {
+	db *sql.DB
+}
+
+func NewWorkspaceRepository(db *sql.DB) *WorkspaceRepository {
+	return &WorkspaceRepository{db: db}
+}
+
+// ImportOptions controls how a workspace is merged into the database
+type ImportOptions struct {
+	// ExistingProjects maps document project IDs to projects already in the
+	// database. Those projects are not created; their tasks and notes are
+	// added to the existing ones instead.
+	ExistingProjects map[int64]int64
+}
+
+// ImportResult reports what an import created
+type ImportResult struct {
+	Projects int
+	Tasks    int
+	Notes    int
+
+	// ProjectIDs and TaskIDs map document IDs to the new database IDs
+	ProjectIDs map[int64]int64
+	TaskIDs    map[int64]int64
+}
+
+// Export reads every project, status, task, note and dependency, including
+// archived projects but not the trash
+func (r *WorkspaceRepository) Export() (*models.Workspace, error) {
+	projects, err := queryProjects(r.db, "WHERE deleted_at IS NULL")
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := queryStatuses(r.db, "WHERE project_id IN (SELECT id FROM projects WHERE deleted_at IS NULL)")
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := queryTasks(r.db, "WHERE deleted_at IS NULL")
+	if err != nil {
+		return nil, err
+	}
+	notes, err := queryNotes(r.db, "WHERE deleted_at IS NULL")
+	if err != nil {
+		return nil, err
+	}
+	dependencies, err := queryDependencies(r.db, "WHERE "+dependencyBetween("SELECT id FROM tasks WHERE deleted_at IS NULL"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Workspace{
+		Version:      models.WorkspaceVersion,
+		ExportedAt:   time.Now().UTC(),
+		Projects:     projects,
+		Statuses:     statuses,
+		Tasks:        tasks,
+		Notes:        notes,
+		Dependencies: dependencies,
+	}, nil
+}
+
+// Import inserts every project, status, task, note and dependency of ws
+// with new IDs, keeping their timestamps. Projects without statuses in the
+// document get the default workflow. It runs in a single transaction, so
+// either everything is imported or nothing is.
+func (r *WorkspaceRepository) Import(ws *models.Workspace, opts ImportOptions) (*ImportResult, error) {
+	if ws.Version > models.WorkspaceVersion {
+		return nil, fmt.Errorf("unsupported workspace version %d (newest supported is %d)", ws.Version, models.WorkspaceVersion)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result := &ImportResult{
+		ProjectIDs: make(map[int64]int64, len(ws.Projects)),
+		TaskIDs:    make(map[int64]int64, len(ws.Tasks)),
+	}
+	for docID, dbID := range opts.ExistingProjects {
+		result.ProjectIDs[docID] = dbID
+	}
+
+	// statuses maps document status IDs to the imported ones
+	statuses := make(map[int64]models.Status)
+
+	// Projects
+	projectQuery := `
+		INSERT INTO projects (name, description, due_date, archived, manual_order, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP), COALESCE(?, CURRENT_TIMESTAMP))
+	`
+	for _, project := range ws.Projects {
+		if _, ok := result.ProjectIDs[project.ID]; ok {
+			if _, existing := opts.ExistingProjects[project.ID]; existing {
+				continue
+			}
+			return nil, fmt.Errorf("duplicate project ID %d", project.ID)
+		}
+
+		var dueDate any
+		if project.DueDate.Valid {
+			dueDate = project.DueDate.Time.UTC().Format(timestampLayout)
+		}
+
+		res, err := tx.Exec(projectQuery,
+			project.Name,
+			project.Description,
+			dueDate,
+			project.Archived,
+			project.ManualOrder,
+			formatTimestamp(project.CreatedAt),
+			formatTimestamp(project.UpdatedAt),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import project %q: %w", project.Name, err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get project ID: %w", err)
+		}
+		result.ProjectIDs[project.ID] = id
+		result.Projects++
+
+		if err := importWorkflow(tx, ws.Statuses, project.ID, id, statuses); err != nil {
+			return nil, fmt.Errorf("failed to import workflow of project %q: %w", project.Name, err)
+		}
+	}
+
+	// Tasks, parents before their subtasks. They go after the tasks already
+	// in the project, in the order of their positions in the document.
+	taskQuery := `
+		INSERT INTO tasks (project_id, parent_task_id, title, priority, completed, due_at, start_at, scheduled_for, recurrence, position, status_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP), COALESCE(?, CURRENT_TIMESTAMP))
+	`
+	taskProjects := make(map[int64]int64, len(ws.Tasks))
+	pending := append([]models.Task{}, ws.Tasks...)
+	sortByPosition(pending)
+	for len(pending) > 0 {
+		var deferred []models.Task
+		for _, task := range pending {
+			var parentID *int64
+			if task.ParentTaskID.Valid {
+				id, ok := result.TaskIDs[task.ParentTaskID.Int64]
+				if !ok {
+					deferred = append(deferred, task)
+					continue
+				}
+				parentID = &id
+			}
+
+			if _, ok := result.TaskIDs[task.ID]; ok {
+				return nil, fmt.Errorf("duplicate task ID %d", task.ID)
+			}
+
+			projectID, ok := result.ProjectIDs[task.ProjectID.Int64]
+			if !task.ProjectID.Valid || !ok {
+				return nil, fmt.Errorf("task %q references unknown project %d", task.Title, task.ProjectID.Int64)
+			}
+			if parentID != nil && taskProjects[*parentID] != projectID {
+				return nil, fmt.Errorf("task %q is not in the project of its parent task %d", task.Title, task.ParentTaskID.Int64)
+			}
+
+			if task.Priority < models.PriorityNone || task.Priority > models.PriorityUrgent {
+				return nil, fmt.Errorf("task %q has invalid priority %d", task.Title, task.Priority)
+			}
+
+			if task.Recurrence.Valid {
+				if _, err := recurrence.Parse(task.Recurrence.String); err != nil {
+					return nil, fmt.Errorf("task %q: %w", task.Title, err)
+				}
+			}
+
+			position, err := nextPosition(tx, projectID, parentID)
+			if err != nil {
+				return nil, err
+			}
+
+			// Tasks keep their status when it was imported with the
+			// project, and start in the workflow otherwise
+			completed := task.Completed
+			var statusID *int64
+			if status, ok := statuses[task.StatusID.Int64]; task.StatusID.Valid && ok && status.ProjectID == projectID {
+				statusID = &status.ID
+				completed = status.Done
+			} else {
+				statusID, err = statusFor(tx, projectID, completed)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			dates := task.Dates()
+			res, err := tx.Exec(taskQuery,
+				projectID,
+				parentID,
+				task.Title,
+				task.Priority,
+				completed,
+				dateValue(dates.DueAt, timestampLayout),
+				dateValue(dates.StartAt, timestampLayout),
+				dateValue(dates.ScheduledFor, dateLayout),
+				task.Recurrence,
+				position,
+				statusID,
+				formatTimestamp(task.CreatedAt),
+				formatTimestamp(task.UpdatedAt),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to import task %q: %w", task.Title, err)
+			}
+
+			id, err := res.LastInsertId()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get task ID: %w", err)
+			}
+			result.TaskIDs[task.ID] = id
+			taskProjects[id] = projectID
+			result.Tasks++
+		}
+
+		if len(deferred) == len(pending) {
+			task := deferred[0]
+			return nil, fmt.Errorf("task %q references unknown parent task %d", task.Title, task.ParentTaskID.Int64)
+		}
+		pending = deferred
+	}
+
+	// Notes
+	noteQuery := `
+		INSERT INTO notes (project_id, task_id, content, is_description, created_at, updated_at)
+		VALUES (?, ?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP), COALESCE(?, CURRENT_TIMESTAMP))
+	`
+	for _, note := range ws.Notes {
+		var projectID, taskID *int64
+		switch {
+		case note.ProjectID.Valid && note.TaskID.Valid:
+			return nil, fmt.Errorf("note %d belongs to both a project and a task", note.ID)
+		case note.ProjectID.Valid:
+			id, ok := result.ProjectIDs[note.ProjectID.Int64]
+			if !ok {
+				return nil, fmt.Errorf("note %d references unknown project %d", note.ID, note.ProjectID.Int64)
+			}
+			projectID = &id
+		case note.TaskID.Valid:
+			id, ok := result.TaskIDs[note.TaskID.Int64]
+			if !ok {
+				return nil, fmt.Errorf("note %d references unknown task %d", note.ID, note.TaskID.Int64)
+			}
+			taskID = &id
+		default:
+			return nil, fmt.Errorf("note %d belongs to neither a project nor a task", note.ID)
+		}
+
+		_, err := tx.Exec(noteQuery,
+			projectID,
+			taskID,
+			note.Content,
+			note.IsDescription,
+			formatTimestamp(note.CreatedAt),
+			formatTimestamp(note.UpdatedAt),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import note %d: %w", note.ID, err)
+		}
+		result.Notes++
+	}
+
+	// Dependencies, rejecting cycles like links made by hand
+	for _, dependency := range ws.Dependencies {
+		taskID, ok := result.TaskIDs[dependency.TaskID]
+		if !ok {
+			return nil, fmt.Errorf("dependency references unknown task %d", dependency.TaskID)
+		}
+		blockerID, ok := result.TaskIDs[dependency.DependsOnID]
+		if !ok {
+			return nil, fmt.Errorf("dependency references unknown task %d", dependency.DependsOnID)
+		}
+
+		if err := addDependency(tx, taskID, blockerID); err != nil {
+			return nil, fmt.Errorf("failed to import dependency of task %d on %d: %w", dependency.TaskID, dependency.DependsOnID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// ProjectSubtree reads a project with its workflow and all its tasks and
+// notes
+func (r *WorkspaceRepository) ProjectSubtree(id int64) (*models.Workspace, error) {
+	projects, err := queryProjects(r.db, "WHERE id = ? AND deleted_at IS NULL", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(projects) == 0 {
+		return nil, fmt.Errorf("project not found")
+	}
+
+	statuses, err := queryStatuses(r.db, "WHERE project_id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := queryTasks(r.db, "WHERE project_id = ? AND deleted_at IS NULL", id)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := queryNotes(r.db, "WHERE deleted_at IS NULL AND (project_id = ? OR task_id IN (SELECT id FROM tasks WHERE project_id = ?))", id, id)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies, err := queryDependencies(r.db, "WHERE "+dependencyBetween("SELECT id FROM tasks WHERE project_id = ? AND deleted_at IS NULL"), id, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Workspace{
+		Version:      models.WorkspaceVersion,
+		ExportedAt:   time.Now().UTC(),
+		Projects:     projects,
+		Statuses:     statuses,
+		Tasks:        tasks,
+		Notes:        notes,
+		Dependencies: dependencies,
+	}, nil
+}
+
+// TaskSubtree reads a task with all its subtasks and their notes
+func (r *WorkspaceRepository) TaskSubtree(id int64) (*models.Workspace, error) {
+	tasks, err := queryTasks(r.db, "WHERE deleted_at IS NULL AND id IN ("+taskSubtreeIDs+")", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	notes, err := queryNotes(r.db, "WHERE deleted_at IS NULL AND task_id IN ("+taskSubtreeIDs+")", id)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies, err := queryDependencies(r.db, "WHERE "+dependencyBetween("SELECT id FROM tasks WHERE deleted_at IS NULL AND id IN ("+taskSubtreeIDs+")"), id, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Workspace{
+		Version:      models.WorkspaceVersion,
+		ExportedAt:   time.Now().UTC(),
+		Projects:     []models.Project{},
+		Statuses:     models.Workflow{},
+		Tasks:        tasks,
+		Notes:        notes,
+		Dependencies: dependencies,
+	}, nil
+}
+
+// importWorkflow creates the workflow of an imported project from the
+// document's statuses for it, or the default workflow when it has none, and
+// records the new statuses under their document IDs
+func importWorkflow(tx *sql.Tx, docStatuses []models.Status, docProjectID, projectID int64, statuses map[int64]models.Status) error {
+	var workflow models.Workflow
+	for _, status := range docStatuses {
+		if status.ProjectID == docProjectID {
+			workflow = append(workflow, status)
+		}
+	}
+	sort.SliceStable(workflow, func(i, j int) bool {
+		return workflow[i].Position < workflow[j].Position
+	})
+	if len(workflow) == 0 {
+		workflow = models.DefaultWorkflow
+	}
+
+	ids, err := createWorkflow(tx, projectID, workflow)
+	if err != nil {
+		return err
+	}
+
+	created := make(map[int64]int64, len(workflow))
+	for i, status := range workflow {
+		if status.ID == 0 {
+			continue
+		}
+		if _, ok := statuses[status.ID]; ok {
+			return fmt.Errorf("duplicate status ID %d", status.ID)
+		}
+		created[status.ID] = ids[i]
+		statuses[status.ID] = models.Status{ID: ids[i], ProjectID: projectID, Done: status.Done}
+	}
+
+	for _, status := range workflow {
+		for _, next := range status.Next {
+			toID, ok := created[next]
+			if !ok {
+				return fmt.Errorf("status %q leads to unknown status %d", status.Name, next)
+			}
+			if toID == created[status.ID] {
+				return fmt.Errorf("status %q cannot lead to itself", status.Name)
+			}
+			if err := addTransition(tx, created[status.ID], toID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// dependencyBetween matches dependencies whose tasks are both selected by
+// the task ID query, which then takes its arguments twice
+func dependencyBetween(taskIDs string) string {
+	return "task_id IN (" + taskIDs + ") AND depends_on_id IN (" + taskIDs + ")"
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// queryProjects reads the projects matching the where clause, ordered by ID
+func queryProjects(q queryer, where string, args ...any) ([]models.Project, error) {
+	rows, err := q.Query(`
+		SELECT id, name, description, due_date, archived, manual_order, created_at, updated_at
+		FROM projects
+		`+where+`
+		ORDER BY id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get projects: %w", err)
+	}
+	defer rows.Close()
+
+	projects := []models.Project{}
+	for rows.Next() {
+		var project models.Project
+		err := rows.Scan(
+			&project.ID,
+			&project.Name,
+			&project.Description,
+			&project.DueDate,
+			&project.Archived,
+			&project.ManualOrder,
+			&project.CreatedAt,
+			&project.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan project: %w", err)
+		}
+		projects = append(projects, project)
+	}
+
+	return projects, rows.Err()
+}
+
+// queryTasks reads the tasks matching the where clause, ordered by ID
+func queryTasks(q queryer, where string, args ...any) ([]models.Task, error) {
+	rows, err := q.Query(`
+		SELECT id, project_id, parent_task_id, title, priority, completed, due_at, start_at, scheduled_for, recurrence, position, status_id, created_at, updated_at
+		FROM tasks
+		`+where+`
+		ORDER BY id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []models.Task{}
+	for rows.Next() {
+		var task models.Task
+		err := rows.Scan(
+			&task.ID,
+			&task.ProjectID,
+			&task.ParentTaskID,
+			&task.Title,
+			&task.Priority,
+			&task.Completed,
+			&task.DueAt,
+			&task.StartAt,
+			&task.ScheduledFor,
+			&task.Recurrence,
+			&task.Position,
+			&task.StatusID,
+			&task.CreatedAt,
+			&task.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// queryNotes reads the notes matching the where clause, ordered by ID
+func queryNotes(q queryer, where string, args ...any) ([]models.Note, error) {
+	rows, err := q.Query(`
+		SELECT id, project_id, task_id, content, is_description, created_at, updated_at
+		FROM notes
+		`+where+`
+		ORDER BY id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := []models.Note{}
+	for rows.Next() {
+		var note models.Note
+		err := rows.Scan(
+			&note.ID,
+			&note.ProjectID,
+			&note.TaskID,
+			&note.Content,
+			&note.IsDescription,
+			&note.CreatedAt,
+			&note.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan note: %w", err)
+		}
+		notes = append(notes, note)
+	}
+
+	return notes, rows.Err()
+}
+
+// formatTimestamp formats t like CURRENT_TIMESTAMP, or returns nil for the
+// zero time so the column default applies
+func formatTimestamp(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(timestampLayout)
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+
+	"palco/internal/database/models"
+)
+
+func TestImportRemapsIDs(t *testing.T) {
+	r := newRepos(t)
+	project := r.project(t, "Home")
+	parent := r.task(t, project.ID, nil, "Paint the house")
+	child := r.task(t, project.ID, &parent.ID, "Buy paint")
+	if err := r.dependencies.Add(parent.ID, child.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.notes.CreateForProject(project.ID, "Ask the neighbours"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.notes.CreateForTask(child.ID, "Matte, not gloss"); err != nil {
+		t.Fatal(err)
+	}
+
+	ws, err := r.workspace.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Importing the export into the same database copies everything
+	result, err := r.workspace.Import(ws, ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Projects != 1 || result.Tasks != 2 || result.Notes != 2 {
+		t.Fatalf("imported %d projects, %d tasks and %d notes, want 1, 2 and 2", result.Projects, result.Tasks, result.Notes)
+	}
+
+	projectID := result.ProjectIDs[project.ID]
+	parentID, childID := result.TaskIDs[parent.ID], result.TaskIDs[child.ID]
+	if projectID == project.ID || parentID == parent.ID || childID == child.ID {
+		t.Fatalf("IDs were not remapped: %v, %v", result.ProjectIDs, result.TaskIDs)
+	}
+
+	got, err := r.tasks.GetByID(childID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ProjectID.Int64 != projectID || got.ParentTaskID.Int64 != parentID {
+		t.Errorf("child project = %d, parent = %d, want %d and %d", got.ProjectID.Int64, got.ParentTaskID.Int64, projectID, parentID)
+	}
+
+	blocked, err := r.dependencies.Exists(parentID, childID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !blocked {
+		t.Errorf("task #%d is not blocked by task #%d", parentID, childID)
+	}
+
+	notes, err := r.notes.GetByTaskID(childID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 1 || notes[0].Content != "Matte, not gloss" {
+		t.Errorf("child notes = %+v, want the imported note", notes)
+	}
+
+	// The originals are left alone
+	notes, err = r.notes.GetByTaskID(child.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 1 {
+		t.Errorf("original child has %d notes, want 1", len(notes))
+	}
+}
+
+func TestImportRollsBack(t *testing.T) {
+	id := func(n int64) sql.NullInt64 { return sql.NullInt64{Int64: n, Valid: true} }
+	projects := []models.Project{{ID: 1, Name: "Home"}, {ID: 2, Name: "Work"}}
+	tasks := []models.Task{
+		{ID: 10, ProjectID: id(1), Title: "Paint the house"},
+		{ID: 11, ProjectID: id(1), ParentTaskID: id(10), Title: "Buy paint"},
+		{ID: 20, ProjectID: id(2), Title: "Write report"},
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(ws *models.Workspace)
+	}{
+		{"invalid priority", func(ws *models.Workspace) {
+			ws.Tasks[2].Priority = models.PriorityUrgent + 1
+		}},
+		{"parent in another project", func(ws *models.Workspace) {
+			ws.Tasks = append(ws.Tasks, models.Task{ID: 21, ProjectID: id(2), ParentTaskID: id(10), Title: "Misfiled"})
+		}},
+		{"unknown project", func(ws *models.Workspace) {
+			ws.Tasks[2].ProjectID = id(3)
+		}},
+		{"unknown parent", func(ws *models.Workspace) {
+			ws.Tasks[2].ParentTaskID = id(99)
+		}},
+		{"invalid repeat rule", func(ws *models.Workspace) {
+			ws.Tasks[2].Recurrence = sql.NullString{String: "FREQ=HOURLY", Valid: true}
+		}},
+		{"duplicate task", func(ws *models.Workspace) {
+			ws.Tasks[2].ID = 10
+		}},
+		{"dependency cycle", func(ws *models.Workspace) {
+			ws.Dependencies = []models.Dependency{{TaskID: 10, DependsOnID: 11}, {TaskID: 11, DependsOnID: 10}}
+		}},
+		{"orphan note", func(ws *models.Workspace) {
+			ws.Notes = []models.Note{{ID: 1, Content: "Lost"}}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRepos(t)
+			r.project(t, "Existing")
+
+			ws := &models.Workspace{
+				Version:  models.WorkspaceVersion,
+				Projects: append([]models.Project{}, projects...),
+				Tasks:    append([]models.Task{}, tasks...),
+			}
+			tt.mutate(ws)
+
+			if _, err := r.workspace.Import(ws, ImportOptions{}); err == nil {
+				t.Fatal("import succeeded, want an error")
+			}
+
+			after, err := r.workspace.Export()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(after.Projects) != 1 || len(after.Tasks) != 0 || len(after.Notes) != 0 || len(after.Dependencies) != 0 {
+				t.Errorf("database has %d projects, %d tasks, %d notes and %d dependencies after a failed import, want only the existing project",
+					len(after.Projects), len(after.Tasks), len(after.Notes), len(after.Dependencies))
+			}
+		})
+	}
+}
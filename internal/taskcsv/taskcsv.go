@@ -0,0 +1,383 @@
+// Package taskcsv reads and writes tasks as CSV with configurable columns
+package taskcsv
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"palco/internal/database/models"
+	"palco/internal/recurrence"
+)
+
+// Columns lists every supported column in their default order
+var Columns = []string{
+	"id",
+	"project",
+	"parent",
+	"title",
+	"priority",
+	"completed",
+	"due_at",
+	"start_at",
+	"scheduled_for",
+	"recurrence",
+	"created_at",
+	"updated_at",
+	"description",
+}
+
+// timeLayouts are accepted for timestamps and dates, the first one is
+// written on export for created_at and updated_at. Task dates are local
+// wall-clock times and are written as YYYY-MM-DD[ HH:MM].
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// Row is a task with the values that live outside the tasks table
+type Row struct {
+	Task        models.Task
+	Project     string
+	Description string
+}
+
+// ParseColumns parses a comma separated column list such as
+// "id,title,priority". An empty spec selects all columns.
+func ParseColumns(spec string) ([]string, error) {
+	if strings.TrimSpace(spec) == "" {
+		return Columns, nil
+	}
+
+	var columns []string
+	for _, column := range strings.Split(spec, ",") {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if !slices.Contains(Columns, column) {
+			return nil, fmt.Errorf("unknown column %q (available: %s)", column, strings.Join(Columns, ", "))
+		}
+		if slices.Contains(columns, column) {
+			return nil, fmt.Errorf("duplicate column %q", column)
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// Write writes a header followed by one record per row
+func Write(w io.Writer, columns []string, rows []Row) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	record := make([]string, len(columns))
+	for _, row := range rows {
+		for i, column := range columns {
+			record[i] = value(row, column)
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+func value(row Row, column string) string {
+	task := row.Task
+	switch column {
+	case "id":
+		return strconv.FormatInt(task.ID, 10)
+	case "project":
+		return row.Project
+	case "parent":
+		if task.ParentTaskID.Valid {
+			return strconv.FormatInt(task.ParentTaskID.Int64, 10)
+		}
+		return ""
+	case "title":
+		return task.Title
+	case "priority":
+		return strconv.Itoa(task.Priority)
+	case "completed":
+		return strconv.FormatBool(task.Completed)
+	case "due_at":
+		return formatDate(task.DueAt)
+	case "start_at":
+		return formatDate(task.StartAt)
+	case "scheduled_for":
+		return formatDate(task.ScheduledFor)
+	case "recurrence":
+		return task.Recurrence.String
+	case "created_at":
+		return task.CreatedAt.UTC().Format(timeLayouts[0])
+	case "updated_at":
+		return task.UpdatedAt.UTC().Format(timeLayouts[0])
+	case "description":
+		return row.Description
+	default:
+		return ""
+	}
+}
+
+// Parse reads CSV with a header row into a workspace. Columns are matched
+// by header name in any order; only title is required. Tasks without a
+// project go to defaultProject. The parent column refers to the id column
+// of another row, or to a 1-based data row number when there is no id
+// column. Every invalid row is reported, with its line number.
+func Parse(r io.Reader, defaultProject string) (*models.Workspace, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("empty CSV")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !slices.Contains(Columns, name) {
+			return nil, fmt.Errorf("unknown column %q in header", name)
+		}
+		if _, ok := index[name]; ok {
+			return nil, fmt.Errorf("duplicate column %q in header", name)
+		}
+		index[name] = i
+	}
+	if _, ok := index["title"]; !ok {
+		return nil, fmt.Errorf("missing required column \"title\"")
+	}
+
+	ws := &models.Workspace{Version: models.WorkspaceVersion}
+	projectIDs := make(map[string]int64)
+	rowIDs := make(map[string]int64) // id or row number to document task ID
+	lines := make(map[int64]int)     // document task ID to line number
+	parents := make(map[int64]string)
+	var errs []error
+
+	for row := 1; ; row++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		line, _ := cr.FieldPos(0)
+		field := func(column string) string {
+			if i, ok := index[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		rowErr := func(format string, args ...any) {
+			errs = append(errs, fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...)))
+		}
+
+		if isBlank(record) {
+			continue
+		}
+
+		task := models.Task{ID: int64(len(ws.Tasks) + 1), Title: field("title")}
+		if task.Title == "" {
+			rowErr("title is empty")
+		}
+
+		if p := field("priority"); p != "" {
+			priority, err := strconv.Atoi(p)
+			if err != nil || priority < models.PriorityNone || priority > models.PriorityUrgent {
+				rowErr("invalid priority %q (expected 0-4)", p)
+			}
+			task.Priority = priority
+		}
+
+		if c := field("completed"); c != "" {
+			completed, err := parseBool(c)
+			if err != nil {
+				rowErr("invalid completed value %q", c)
+			}
+			task.Completed = completed
+		}
+
+		timestamps := []struct {
+			column string
+			target *time.Time
+		}{
+			{"created_at", &task.CreatedAt},
+			{"updated_at", &task.UpdatedAt},
+		}
+		for _, ts := range timestamps {
+			if v := field(ts.column); v != "" {
+				t, err := parseTime(v)
+				if err != nil {
+					rowErr("invalid %s %q", ts.column, v)
+				}
+				*ts.target = t
+			}
+		}
+
+		dates := []struct {
+			column string
+			target *sql.NullTime
+		}{
+			{"due_at", &task.DueAt},
+			{"start_at", &task.StartAt},
+			{"scheduled_for", &task.ScheduledFor},
+		}
+		for _, date := range dates {
+			if v := field(date.column); v != "" {
+				t, err := parseTime(v)
+				if err != nil {
+					rowErr("invalid %s %q", date.column, v)
+				}
+				*date.target = sql.NullTime{Time: t, Valid: err == nil}
+			}
+		}
+
+		// Accept repeat rules typed as text as well as stored ones
+		if v := field("recurrence"); v != "" {
+			rule, err := recurrence.ParseText(v)
+			if err != nil {
+				rowErr("invalid recurrence %q", v)
+			}
+			task.Recurrence = sql.NullString{String: rule.String(), Valid: err == nil}
+		}
+
+		project := field("project")
+		if project == "" {
+			project = defaultProject
+		}
+		key := strings.ToLower(project)
+		if _, ok := projectIDs[key]; !ok {
+			projectIDs[key] = int64(len(ws.Projects) + 1)
+			ws.Projects = append(ws.Projects, models.Project{ID: projectIDs[key], Name: project})
+		}
+		task.ProjectID.Int64, task.ProjectID.Valid = projectIDs[key], true
+
+		ref := strconv.Itoa(row)
+		if _, ok := index["id"]; ok {
+			ref = field("id")
+		}
+		if ref != "" {
+			if _, ok := rowIDs[ref]; ok {
+				rowErr("duplicate id %q", ref)
+			}
+			rowIDs[ref] = task.ID
+		}
+		if parent := field("parent"); parent != "" {
+			parents[task.ID] = parent
+		}
+		lines[task.ID] = line
+
+		ws.Tasks = append(ws.Tasks, task)
+
+		if description := field("description"); description != "" {
+			note := models.Note{ID: int64(len(ws.Notes) + 1), Content: description, IsDescription: true}
+			note.TaskID.Int64, note.TaskID.Valid = task.ID, true
+			ws.Notes = append(ws.Notes, note)
+		}
+	}
+
+	// Resolve parents once every row is known, so children may come first
+	for i := range ws.Tasks {
+		task := &ws.Tasks[i]
+		ref, ok := parents[task.ID]
+		if !ok {
+			continue
+		}
+
+		parentID, ok := rowIDs[ref]
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("line %d: parent %q does not match any row", lines[task.ID], ref))
+		case parentID == task.ID:
+			errs = append(errs, fmt.Errorf("line %d: task is its own parent", lines[task.ID]))
+		case ws.Tasks[parentID-1].ProjectID != task.ProjectID:
+			errs = append(errs, fmt.Errorf("line %d: parent on line %d is in a different project", lines[task.ID], lines[parentID]))
+		default:
+			task.ParentTaskID.Int64, task.ParentTaskID.Valid = parentID, true
+		}
+	}
+
+	for _, task := range ws.Tasks {
+		if inCycle(ws.Tasks, task.ID) {
+			errs = append(errs, fmt.Errorf("line %d: parent references form a cycle", lines[task.ID]))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return ws, nil
+}
+
+// inCycle reports whether following parents from id leads back to id
+func inCycle(tasks []models.Task, id int64) bool {
+	seen := make(map[int64]bool)
+	for current := tasks[id-1]; current.ParentTaskID.Valid; current = tasks[current.ParentTaskID.Int64-1] {
+		if current.ParentTaskID.Int64 == id {
+			return true
+		}
+		if seen[current.ID] {
+			return false
+		}
+		seen[current.ID] = true
+	}
+	return false
+}
+
+func formatDate(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return models.FormatTaskDate(t.Time)
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "1", "yes", "y", "x", "done":
+		return true, nil
+	case "false", "0", "no", "n", "":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid boolean %q", s)
+	}
+}
+
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+func isBlank(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,183 @@
+package taskcsv
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"palco/internal/database/models"
+)
+
+func TestParseColumns(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []string
+		wantErr bool
+	}{
+		{"", Columns, false},
+		{"id,title,priority", []string{"id", "title", "priority"}, false},
+		{" Title , DUE_AT ", []string{"title", "due_at"}, false},
+		{"title,colour", nil, true},
+		{"title,title", nil, true},
+		{"title,", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseColumns(tt.spec)
+			if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseColumns(%q) = %v, %v, want %v (error %v)", tt.spec, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+		want []string // "project/parent title priority completed" per task
+	}{
+		{
+			"parents by id, children first",
+			"id,parent,project,title,priority,completed\n" +
+				"3,1,Home,Buy rollers,0,yes\n" +
+				"1,,Home,Paint,4,\n" +
+				"7,3,Home,Compare prices,1,no\n" +
+				"9,,,Call mom,2,x\n",
+			[]string{"Home/2 Buy rollers 0 true", "Home/0 Paint 4 false", "Home/1 Compare prices 1 false", "Inbox/0 Call mom 2 true"},
+		},
+		{
+			"parents by row number",
+			"title,parent\n" +
+				"Paint,\n" +
+				"\n" +
+				"Buy rollers,1\n" +
+				"Compare prices,2\n",
+			[]string{"Inbox/0 Paint 0 false", "Inbox/1 Buy rollers 0 false", "Inbox/2 Compare prices 0 false"},
+		},
+		{
+			"any column order and a byte order mark",
+			"\ufeffPriority, Title ,Project\n" +
+				"3,Paint,home\n" +
+				"0,Mop,HOME\n",
+			[]string{"home/0 Paint 3 false", "home/0 Mop 0 false"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws, err := Parse(strings.NewReader(tt.csv), "Inbox")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			names := make(map[int64]string)
+			for _, project := range ws.Projects {
+				names[project.ID] = project.Name
+			}
+			var got []string
+			for _, task := range ws.Tasks {
+				got = append(got, fmt.Sprintf("%s/%d %s %d %v",
+					names[task.ProjectID.Int64], task.ParentTaskID.Int64, task.Title, task.Priority, task.Completed))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tasks =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+		want string // Part of the error message
+	}{
+		{"empty", "", "empty CSV"},
+		{"unknown column", "title,colour\nPaint,red\n", `unknown column "colour"`},
+		{"duplicate column", "title,Title\nPaint,Paint\n", `duplicate column "title"`},
+		{"missing title column", "id,priority\n1,2\n", `missing required column "title"`},
+		{"empty title", "id,title\n1,\n", "line 2: title is empty"},
+		{"priority above bounds", "title,priority\nPaint,5\n", `line 2: invalid priority "5"`},
+		{"priority below bounds", "title,priority\nPaint,-1\n", `line 2: invalid priority "-1"`},
+		{"priority as text", "title,priority\nPaint,high\n", `line 2: invalid priority "high"`},
+		{"completed", "title,completed\nPaint,maybe\n", `line 2: invalid completed value "maybe"`},
+		{"due date", "title,due_at\nPaint,next week\n", `line 2: invalid due_at "next week"`},
+		{"created_at", "title,created_at\nPaint,31/12/2026\n", `line 2: invalid created_at "31/12/2026"`},
+		{"recurrence", "title,recurrence\nPaint,sometimes\n", `line 2: invalid recurrence "sometimes"`},
+		{"duplicate id", "id,title\n1,Paint\n1,Mop\n", `line 3: duplicate id "1"`},
+		{"unknown parent", "id,title,parent\n1,Paint,2\n", `line 2: parent "2" does not match any row`},
+		{"own parent", "id,title,parent\n1,Paint,1\n", "line 2: task is its own parent"},
+		{"parent in another project", "id,title,project,parent\n1,Paint,Home,\n2,Report,Work,1\n", "line 3: parent on line 2 is in a different project"},
+		{"cycle", "id,title,parent\n1,Paint,2\n2,Mop,1\n", "line 2: parent references form a cycle"},
+		{"unterminated quote", "title\n\"Paint\n", "failed to read CSV"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws, err := Parse(strings.NewReader(tt.csv), "Inbox")
+			if err == nil {
+				t.Fatalf("Parse = %+v, want an error", ws)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+
+	// Every invalid row is reported at once
+	_, err := Parse(strings.NewReader("title,priority\n,1\nPaint,9\n"), "Inbox")
+	if err == nil || !strings.Contains(err.Error(), "line 2:") || !strings.Contains(err.Error(), "line 3:") {
+		t.Errorf("error = %v, want both invalid lines reported", err)
+	}
+}
+
+func TestWriteParse(t *testing.T) {
+	id := func(n int64) sql.NullInt64 { return sql.NullInt64{Int64: n, Valid: true} }
+	date := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+	created := time.Date(2026, time.October, 1, 8, 30, 0, 0, time.UTC)
+
+	rows := []Row{
+		{Project: "Home", Description: "Two coats,\n\"matte\" finish", Task: models.Task{
+			ID: 1, ProjectID: id(1), Title: "Paint, then dry", Priority: models.PriorityUrgent,
+			DueAt: date(time.Date(2026, time.November, 1, 17, 0, 0, 0, time.UTC)), CreatedAt: created, UpdatedAt: created,
+		}},
+		{Project: "Home", Task: models.Task{
+			ID: 2, ProjectID: id(1), ParentTaskID: id(1), Title: "Buy rollers", Completed: true,
+			ScheduledFor: date(time.Date(2026, time.October, 4, 0, 0, 0, 0, time.UTC)), CreatedAt: created, UpdatedAt: created.Add(time.Hour),
+		}},
+		{Project: "Work", Task: models.Task{
+			ID: 3, ProjectID: id(2), Title: "Weekly report", Priority: models.PriorityLow,
+			Recurrence: sql.NullString{String: "FREQ=WEEKLY;BYDAY=FR", Valid: true}, CreatedAt: created, UpdatedAt: created,
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, Columns, rows); err != nil {
+		t.Fatal(err)
+	}
+	ws, err := Parse(&buf, "Inbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ws.Projects) != 2 || ws.Projects[0].Name != "Home" || ws.Projects[1].Name != "Work" {
+		t.Errorf("projects = %+v, want Home and Work", ws.Projects)
+	}
+	if len(ws.Tasks) != len(rows) {
+		t.Fatalf("got %d tasks, want %d", len(ws.Tasks), len(rows))
+	}
+	for i, row := range rows {
+		if got := ws.Tasks[i]; got != row.Task {
+			t.Errorf("task %d = %+v, want %+v", i+1, got, row.Task)
+		}
+	}
+	if len(ws.Notes) != 1 || ws.Notes[0].Content != rows[0].Description || !ws.Notes[0].IsDescription || ws.Notes[0].TaskID.Int64 != 1 {
+		t.Errorf("notes = %+v, want the first task's description", ws.Notes)
+	}
+}
@@ -0,0 +1,261 @@
+// Package taskwarrior imports the JSON written by Taskwarrior's
+// "task export"
+package taskwarrior
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"palco/internal/database/models"
+)
+
+// timeLayout is Taskwarrior's ISO 8601 basic format
+const timeLayout = "20060102T150405Z"
+
+// Task is one entry of "task export"
+type Task struct {
+	UUID        string       `json:"uuid"`
+	Description string       `json:"description"`
+	Project     string       `json:"project"`
+	Priority    string       `json:"priority"`
+	Status      string       `json:"status"`
+	Entry       string       `json:"entry"`
+	Modified    string       `json:"modified"`
+	End         string       `json:"end"`
+	Due         string       `json:"due"`
+	Wait        string       `json:"wait"`
+	Scheduled   string       `json:"scheduled"`
+	Tags        []string     `json:"tags"`
+	Annotations []Annotation `json:"annotations"`
+	Depends     Depends      `json:"depends"`
+}
+
+// Annotation is a timestamped note on a task
+type Annotation struct {
+	Entry       string `json:"entry"`
+	Description string `json:"description"`
+}
+
+// Depends lists the UUIDs a task depends on. Taskwarrior 2.6+ writes an
+// array, older versions a comma separated string.
+type Depends []string
+
+func (d *Depends) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*d = list
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid depends: %s", data)
+	}
+	*d = nil
+	for _, uuid := range strings.Split(s, ",") {
+		if uuid = strings.TrimSpace(uuid); uuid != "" {
+			*d = append(*d, uuid)
+		}
+	}
+	return nil
+}
+
+// Options controls how Taskwarrior data maps onto palco
+type Options struct {
+	// DefaultProject receives tasks without a project
+	DefaultProject string
+
+	// SplitProjects turns dotted project names such as "home.garden" into
+	// the project "home" with a "garden" task grouping the tasks. When
+	// false the dotted name is kept as the project name.
+	SplitProjects bool
+}
+
+// Parse reads "task export" output into a workspace. Deleted tasks and
+// recurrence templates are skipped. H/M/L map to High/Medium/Low,
+// annotations become task notes, and tags are kept in a note. due,
+// scheduled and wait become the due, scheduled and start dates.
+// Dependencies on other imported tasks are kept as dependencies.
+func Parse(r io.Reader, opts Options) (*models.Workspace, error) {
+	var exported []Task
+	if err := json.NewDecoder(r).Decode(&exported); err != nil {
+		return nil, fmt.Errorf("failed to parse taskwarrior export: %w", err)
+	}
+
+	b := &builder{
+		ws:         &models.Workspace{Version: models.WorkspaceVersion},
+		opts:       opts,
+		projectIDs: make(map[string]int64),
+		groupIDs:   make(map[string]int64),
+		taskIDs:    make(map[string]int64),
+	}
+
+	var tasks []Task
+	for _, task := range exported {
+		if task.Status == "deleted" || task.Status == "recurring" {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+
+	for _, task := range tasks {
+		b.addTask(task)
+	}
+	b.linkDependencies(tasks)
+
+	return b.ws, nil
+}
+
+type builder struct {
+	ws   *models.Workspace
+	opts Options
+
+	projectIDs map[string]int64 // Project name to document ID
+	groupIDs   map[string]int64 // Dotted subproject path to grouping task ID
+	taskIDs    map[string]int64 // UUID to document task ID
+}
+
+func (b *builder) addTask(tw Task) {
+	projectID, parentID := b.placement(tw.Project)
+
+	task := models.Task{
+		ID:        int64(len(b.ws.Tasks) + 1),
+		Title:     strings.TrimSpace(tw.Description),
+		Priority:  priority(tw.Priority),
+		Completed: tw.Status == "completed",
+		CreatedAt: parseTime(tw.Entry),
+		UpdatedAt: parseTime(tw.Modified),
+	}
+	task.ProjectID.Int64, task.ProjectID.Valid = projectID, true
+	if parentID != 0 {
+		task.ParentTaskID.Int64, task.ParentTaskID.Valid = parentID, true
+	}
+	if task.Completed && tw.End != "" {
+		task.UpdatedAt = parseTime(tw.End)
+	}
+	task.DueAt = localTime(tw.Due)
+	task.StartAt = localTime(tw.Wait)
+	task.ScheduledFor = localTime(tw.Scheduled)
+	if task.Title == "" {
+		task.Title = "(untitled)"
+	}
+
+	b.ws.Tasks = append(b.ws.Tasks, task)
+	if tw.UUID != "" {
+		b.taskIDs[tw.UUID] = task.ID
+	}
+
+	for _, annotation := range tw.Annotations {
+		b.addNote(task.ID, annotation.Description, parseTime(annotation.Entry))
+	}
+
+	if len(tw.Tags) > 0 {
+		b.addNote(task.ID, "Tags: "+strings.Join(tw.Tags, ", "), task.CreatedAt)
+	}
+}
+
+// placement returns the project and grouping parent task for a Taskwarrior
+// project name
+func (b *builder) placement(project string) (projectID, parentID int64) {
+	segments := []string{project}
+	if b.opts.SplitProjects {
+		segments = strings.Split(project, ".")
+	}
+
+	name := strings.TrimSpace(segments[0])
+	if name == "" {
+		name = b.opts.DefaultProject
+	}
+
+	projectID, ok := b.projectIDs[name]
+	if !ok {
+		projectID = int64(len(b.ws.Projects) + 1)
+		b.projectIDs[name] = projectID
+		b.ws.Projects = append(b.ws.Projects, models.Project{ID: projectID, Name: name})
+	}
+
+	// One grouping task per subproject level
+	path := name
+	for _, segment := range segments[1:] {
+		path += "." + segment
+		groupID, ok := b.groupIDs[path]
+		if !ok {
+			group := models.Task{ID: int64(len(b.ws.Tasks) + 1), Title: segment}
+			group.ProjectID.Int64, group.ProjectID.Valid = projectID, true
+			if parentID != 0 {
+				group.ParentTaskID.Int64, group.ParentTaskID.Valid = parentID, true
+			}
+			b.ws.Tasks = append(b.ws.Tasks, group)
+			groupID = group.ID
+			b.groupIDs[path] = groupID
+		}
+		parentID = groupID
+	}
+
+	return projectID, parentID
+}
+
+// linkDependencies records each task's dependencies on tasks that were
+// imported, which may be in other projects
+func (b *builder) linkDependencies(tasks []Task) {
+	for _, task := range tasks {
+		id, ok := b.taskIDs[task.UUID]
+		if !ok {
+			continue
+		}
+
+		for _, uuid := range task.Depends {
+			if depID, ok := b.taskIDs[uuid]; ok && depID != id {
+				b.ws.Dependencies = append(b.ws.Dependencies, models.Dependency{TaskID: id, DependsOnID: depID})
+			}
+		}
+	}
+}
+
+func (b *builder) addNote(taskID int64, content string, createdAt time.Time) {
+	note := models.Note{
+		ID:        int64(len(b.ws.Notes) + 1),
+		Content:   content,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	note.TaskID.Int64, note.TaskID.Valid = taskID, true
+	b.ws.Notes = append(b.ws.Notes, note)
+}
+
+func priority(p string) int {
+	switch strings.ToUpper(p) {
+	case "H":
+		return models.PriorityHigh
+	case "M":
+		return models.PriorityMedium
+	case "L":
+		return models.PriorityLow
+	default:
+		return models.PriorityNone
+	}
+}
+
+// parseTime parses a Taskwarrior timestamp, returning the zero time when
+// it is empty or malformed
+func parseTime(s string) time.Time {
+	t, err := time.Parse(timeLayout, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// localTime parses a Taskwarrior date into the local wall-clock time palco
+// stores task dates as, or an invalid NullTime when it is unset
+func localTime(s string) sql.NullTime {
+	t := parseTime(s)
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.Local(), Valid: true}
+}
@@ -0,0 +1,123 @@
+package taskwarrior
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"palco/internal/database/models"
+)
+
+const export = `[
+	{"uuid": "a", "description": "Paint fence", "project": "home.garden", "priority": "H", "status": "pending",
+	 "entry": "20261001T080000Z", "due": "20261020T120000Z", "tags": ["outdoor", "weekend"],
+	 "annotations": [{"entry": "20261002T090000Z", "description": "Use the green paint"}]},
+	{"uuid": "b", "description": "Buy paint", "project": "home.garden", "priority": "m", "status": "completed",
+	 "entry": "20261001T080000Z", "end": "20261003T100000Z", "depends": ""},
+	{"uuid": "c", "description": "Mow lawn", "project": "home.garden.lawn", "priority": "L", "status": "waiting",
+	 "depends": ["a", "missing", "c"]},
+	{"uuid": "d", "description": "  ", "project": "home", "status": "pending", "depends": "a,b"},
+	{"uuid": "e", "description": "File taxes", "status": "pending", "priority": "X"},
+	{"uuid": "f", "description": "Old chore", "project": "home", "status": "deleted"},
+	{"uuid": "g", "description": "Weekly review", "status": "recurring"}
+]`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		split bool
+		want  []string // "project/parent title priority completed" per task
+	}{
+		{"dotted names kept", false, []string{
+			"home.garden/0 Paint fence 3 false",
+			"home.garden/0 Buy paint 2 true",
+			"home.garden.lawn/0 Mow lawn 1 false",
+			"home/0 (untitled) 0 false",
+			"Inbox/0 File taxes 0 false",
+		}},
+		{"dotted names split", true, []string{
+			"home/0 garden 0 false",
+			"home/1 Paint fence 3 false",
+			"home/1 Buy paint 2 true",
+			"home/1 lawn 0 false",
+			"home/4 Mow lawn 1 false",
+			"home/0 (untitled) 0 false",
+			"Inbox/0 File taxes 0 false",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws, err := Parse(strings.NewReader(export), Options{DefaultProject: "Inbox", SplitProjects: tt.split})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			names := make(map[int64]string)
+			for _, project := range ws.Projects {
+				names[project.ID] = project.Name
+			}
+			var got []string
+			for _, task := range ws.Tasks {
+				got = append(got, fmt.Sprintf("%s/%d %s %d %v",
+					names[task.ProjectID.Int64], task.ParentTaskID.Int64, task.Title, task.Priority, task.Completed))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tasks =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestParseDetails(t *testing.T) {
+	ws, err := Parse(strings.NewReader(export), Options{DefaultProject: "Inbox"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paint, buy := ws.Tasks[0], ws.Tasks[1]
+	if want := time.Date(2026, time.October, 20, 12, 0, 0, 0, time.UTC).Local(); !paint.DueAt.Valid || !paint.DueAt.Time.Equal(want) {
+		t.Errorf("due = %v, want %v", paint.DueAt, want)
+	}
+	if want := time.Date(2026, time.October, 3, 10, 0, 0, 0, time.UTC); !buy.UpdatedAt.Equal(want) {
+		t.Errorf("completed task updated = %v, want its end time %v", buy.UpdatedAt, want)
+	}
+
+	var notes []string
+	for _, note := range ws.Notes {
+		notes = append(notes, note.Content)
+	}
+	if want := []string{"Use the green paint", "Tags: outdoor, weekend"}; !reflect.DeepEqual(notes, want) {
+		t.Errorf("notes = %q, want %q", notes, want)
+	}
+
+	// Links to missing tasks and to the task itself are dropped
+	want := []models.Dependency{{TaskID: 3, DependsOnID: 1}, {TaskID: 4, DependsOnID: 1}, {TaskID: 4, DependsOnID: 2}}
+	if !reflect.DeepEqual(ws.Dependencies, want) {
+		t.Errorf("dependencies = %+v, want %+v", ws.Dependencies, want)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty", ""},
+		{"not JSON", "uuid: a"},
+		{"truncated", `[{"uuid": "a"`},
+		{"object instead of list", `{"uuid": "a"}`},
+		{"invalid depends", `[{"uuid": "a", "depends": 42}]`},
+		{"invalid tags", `[{"uuid": "a", "tags": "home"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.input), Options{DefaultProject: "Inbox"}); err == nil {
+				t.Error("Parse succeeded, want an error")
+			}
+		})
+	}
+}
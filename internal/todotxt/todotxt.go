@@ -0,0 +1,312 @@
+// Package todotxt reads and writes tasks in the todo.txt format
+// (https://github.com/todotxt/todo.txt)
+package todotxt
+
+import (
+	"bufio"
+	"database/sql"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"palco/internal/database/models"
+)
+
+const dateLayout = "2006-01-02"
+
+var (
+	priorityToken = regexp.MustCompile(`^\(([A-Z])\)$`)
+	dateToken     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	keyValueToken = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_-]*):([^\s:]\S*)$`)
+)
+
+// PriorityFromLetter maps (A)–(D) onto PriorityUrgent..PriorityLow. Letters
+// after D are treated as Low.
+func PriorityFromLetter(letter byte) int {
+	switch letter {
+	case 'A':
+		return models.PriorityUrgent
+	case 'B':
+		return models.PriorityHigh
+	case 'C':
+		return models.PriorityMedium
+	default:
+		return models.PriorityLow
+	}
+}
+
+// PriorityLetter maps a priority onto A–D, or "" for PriorityNone
+func PriorityLetter(priority int) string {
+	switch priority {
+	case models.PriorityUrgent:
+		return "A"
+	case models.PriorityHigh:
+		return "B"
+	case models.PriorityMedium:
+		return "C"
+	case models.PriorityLow:
+		return "D"
+	default:
+		return ""
+	}
+}
+
+// ProjectTag turns a project name into a +project tag body. Tags cannot
+// contain whitespace, so it is replaced with "-".
+func ProjectTag(name string) string {
+	return strings.Join(strings.Fields(name), "-")
+}
+
+// Write writes tasks as todo.txt lines. Each task carries its project as a
+// +tag and its ID and parent as id: and parent: extensions. Completed tasks
+// keep their priority as pri:, since todo.txt drops the (A) prefix on
+// completion, and use their last update as the completion date. Due, start
+// and scheduled dates become due:, t: (threshold) and scheduled:, which
+// hold dates only.
+func Write(w io.Writer, projects []models.Project, tasks []models.Task) error {
+	names := make(map[int64]string, len(projects))
+	for _, project := range projects {
+		names[project.ID] = project.Name
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, task := range tasks {
+		fmt.Fprintln(bw, FormatTask(task, names[task.ProjectID.Int64]))
+	}
+	return bw.Flush()
+}
+
+// FormatTask renders a single task as a todo.txt line
+func FormatTask(task models.Task, projectName string) string {
+	var parts []string
+
+	letter := PriorityLetter(task.Priority)
+	if task.Completed {
+		parts = append(parts, "x", task.UpdatedAt.Format(dateLayout))
+	} else if letter != "" {
+		parts = append(parts, "("+letter+")")
+	}
+	if !task.CreatedAt.IsZero() {
+		parts = append(parts, task.CreatedAt.Format(dateLayout))
+	}
+
+	parts = append(parts, task.Title)
+
+	if tag := ProjectTag(projectName); tag != "" {
+		parts = append(parts, "+"+tag)
+	}
+	if task.ID != 0 {
+		parts = append(parts, "id:"+strconv.FormatInt(task.ID, 10))
+	}
+	if task.ParentTaskID.Valid {
+		parts = append(parts, "parent:"+strconv.FormatInt(task.ParentTaskID.Int64, 10))
+	}
+	if task.Completed && letter != "" {
+		parts = append(parts, "pri:"+letter)
+	}
+	if task.DueAt.Valid {
+		parts = append(parts, "due:"+task.DueAt.Time.Format(dateLayout))
+	}
+	if task.StartAt.Valid {
+		parts = append(parts, "t:"+task.StartAt.Time.Format(dateLayout))
+	}
+	if task.ScheduledFor.Valid {
+		parts = append(parts, "scheduled:"+task.ScheduledFor.Time.Format(dateLayout))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// entry is a parsed line before IDs are resolved
+type entry struct {
+	task    models.Task
+	project string
+	id      string
+	parent  string
+}
+
+// Parse reads todo.txt lines into a workspace. Every distinct +project tag
+// becomes a project; tasks without one go to defaultProject. The first tag
+// of a line is its project, further tags stay in the title. id: and parent:
+// rebuild subtask hierarchies, pri: restores the priority of completed
+// tasks, due:, t: and scheduled: set the task's dates, and every other
+// key:value extension is kept in the title.
+func Parse(r io.Reader, defaultProject string) (*models.Workspace, error) {
+	ws := &models.Workspace{Version: models.WorkspaceVersion}
+
+	var entries []entry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		entries = append(entries, parseLine(line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read todo.txt: %w", err)
+	}
+
+	// Projects in order of first appearance
+	projectIDs := make(map[string]int64)
+	for i := range entries {
+		name := entries[i].project
+		if name == "" {
+			name = defaultProject
+		}
+		key := strings.ToLower(name)
+		if _, ok := projectIDs[key]; !ok {
+			id := int64(len(ws.Projects) + 1)
+			projectIDs[key] = id
+			ws.Projects = append(ws.Projects, models.Project{ID: id, Name: name})
+		}
+		entries[i].task.ProjectID.Int64 = projectIDs[key]
+		entries[i].task.ProjectID.Valid = true
+	}
+
+	// Document task IDs follow line order; id: values are only used to
+	// resolve parent: references
+	taskIDs := make(map[string]int64)
+	for i := range entries {
+		entries[i].task.ID = int64(i + 1)
+		if entries[i].id != "" {
+			taskIDs[entries[i].id] = entries[i].task.ID
+		}
+	}
+
+	for i := range entries {
+		parentID, ok := taskIDs[entries[i].parent]
+		if !ok || parentID == entries[i].task.ID {
+			continue
+		}
+		entries[i].task.ParentTaskID.Int64 = parentID
+		entries[i].task.ParentTaskID.Valid = true
+	}
+
+	// Subtasks live in their parent's project; drop parent links that
+	// would form a cycle
+	for i := range entries {
+		if isCyclic(entries, i) {
+			entries[i].task.ParentTaskID.Valid = false
+		}
+	}
+	for i := range entries {
+		entries[i].task.ProjectID = rootOf(entries, i).task.ProjectID
+	}
+
+	for _, e := range entries {
+		ws.Tasks = append(ws.Tasks, e.task)
+	}
+
+	return ws, nil
+}
+
+func parseLine(line string) entry {
+	var e entry
+	tokens := strings.Fields(line)
+
+	i := 0
+	var completedAt, createdAt time.Time
+	if tokens[0] == "x" {
+		e.task.Completed = true
+		i++
+		if i < len(tokens) && dateToken.MatchString(tokens[i]) {
+			completedAt, _ = time.Parse(dateLayout, tokens[i])
+			i++
+			if i < len(tokens) && dateToken.MatchString(tokens[i]) {
+				createdAt, _ = time.Parse(dateLayout, tokens[i])
+				i++
+			}
+		}
+	} else {
+		if m := priorityToken.FindStringSubmatch(tokens[0]); m != nil {
+			e.task.Priority = PriorityFromLetter(m[1][0])
+			i++
+		}
+		if i < len(tokens) && dateToken.MatchString(tokens[i]) {
+			createdAt, _ = time.Parse(dateLayout, tokens[i])
+			i++
+		}
+	}
+
+	var title []string
+	for _, token := range tokens[i:] {
+		if strings.HasPrefix(token, "+") && len(token) > 1 && e.project == "" {
+			e.project = token[1:]
+			continue
+		}
+
+		if m := keyValueToken.FindStringSubmatch(token); m != nil {
+			switch strings.ToLower(m[1]) {
+			case "id":
+				e.id = m[2]
+				continue
+			case "parent":
+				e.parent = m[2]
+				continue
+			case "pri":
+				if len(m[2]) == 1 && m[2][0] >= 'A' && m[2][0] <= 'Z' {
+					e.task.Priority = PriorityFromLetter(m[2][0])
+					continue
+				}
+			case "due", "t", "scheduled":
+				date, err := time.Parse(dateLayout, m[2])
+				if err != nil {
+					break
+				}
+				value := sql.NullTime{Time: date, Valid: true}
+				switch strings.ToLower(m[1]) {
+				case "due":
+					e.task.DueAt = value
+				case "t":
+					e.task.StartAt = value
+				default:
+					e.task.ScheduledFor = value
+				}
+				continue
+			}
+		}
+
+		title = append(title, token)
+	}
+	e.task.Title = strings.Join(title, " ")
+	if e.task.Title == "" {
+		e.task.Title = line
+	}
+
+	e.task.CreatedAt = createdAt
+	e.task.UpdatedAt = createdAt
+	if !completedAt.IsZero() {
+		e.task.UpdatedAt = completedAt
+	}
+
+	return e
+}
+
+// isCyclic reports whether following parent links from entries[i] leads
+// back to it
+func isCyclic(entries []entry, i int) bool {
+	seen := map[int]bool{i: true}
+	for e := entries[i]; e.task.ParentTaskID.Valid; {
+		j := int(e.task.ParentTaskID.Int64 - 1)
+		if seen[j] {
+			return j == i
+		}
+		seen[j] = true
+		e = entries[j]
+	}
+	return false
+}
+
+// rootOf returns the top-level ancestor of entries[i]
+func rootOf(entries []entry, i int) entry {
+	e := entries[i]
+	for e.task.ParentTaskID.Valid {
+		e = entries[e.task.ParentTaskID.Int64-1]
+	}
+	return e
+}
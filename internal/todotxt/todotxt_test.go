@@ -0,0 +1,167 @@
+package todotxt
+
+import (
+	"bytes"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"palco/internal/database/models"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line      string
+		title     string
+		project   string
+		priority  int
+		completed bool
+		due       time.Time
+		created   time.Time
+	}{
+		{"Call mom", "Call mom", "", models.PriorityNone, false, time.Time{}, time.Time{}},
+		{"(A) Call mom", "Call mom", "", models.PriorityUrgent, false, time.Time{}, time.Time{}},
+		{"(B) Call mom", "Call mom", "", models.PriorityHigh, false, time.Time{}, time.Time{}},
+		{"(C) Call mom", "Call mom", "", models.PriorityMedium, false, time.Time{}, time.Time{}},
+		{"(D) Call mom", "Call mom", "", models.PriorityLow, false, time.Time{}, time.Time{}},
+		{"(Z) Call mom", "Call mom", "", models.PriorityLow, false, time.Time{}, time.Time{}},
+		{"(a) Call mom", "(a) Call mom", "", models.PriorityNone, false, time.Time{}, time.Time{}},
+		{"Call (A) mom", "Call (A) mom", "", models.PriorityNone, false, time.Time{}, time.Time{}},
+		{"(B) 2026-10-01 Call mom +Family @phone", "Call mom @phone", "Family", models.PriorityHigh, false, time.Time{}, day(2026, time.October, 1)},
+		{"Call mom +Family +Weekend", "Call mom +Weekend", "Family", models.PriorityNone, false, time.Time{}, time.Time{}},
+		{"Call mom due:2026-10-20 url:https://example.com", "Call mom url:https://example.com", "", models.PriorityNone, false, day(2026, time.October, 20), time.Time{}},
+		{"Call mom due:soon", "Call mom due:soon", "", models.PriorityNone, false, time.Time{}, time.Time{}},
+		{"x 2026-10-05 2026-10-01 Call mom pri:A", "Call mom", "", models.PriorityUrgent, true, time.Time{}, day(2026, time.October, 1)},
+		{"x Call mom pri:low", "Call mom pri:low", "", models.PriorityNone, true, time.Time{}, time.Time{}},
+		{"xylophone lessons", "xylophone lessons", "", models.PriorityNone, false, time.Time{}, time.Time{}},
+		{"id:1 +Family", "id:1 +Family", "Family", models.PriorityNone, false, time.Time{}, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			e := parseLine(tt.line)
+			if e.task.Title != tt.title || e.project != tt.project || e.task.Priority != tt.priority || e.task.Completed != tt.completed {
+				t.Errorf("parseLine(%q) = %q in %q, priority %d, completed %v, want %q in %q, priority %d, completed %v",
+					tt.line, e.task.Title, e.project, e.task.Priority, e.task.Completed, tt.title, tt.project, tt.priority, tt.completed)
+			}
+			if !e.task.DueAt.Time.Equal(tt.due) || e.task.DueAt.Valid != !tt.due.IsZero() {
+				t.Errorf("parseLine(%q) due = %v, want %v", tt.line, e.task.DueAt, tt.due)
+			}
+			if !e.task.CreatedAt.Equal(tt.created) {
+				t.Errorf("parseLine(%q) created = %v, want %v", tt.line, e.task.CreatedAt, tt.created)
+			}
+		})
+	}
+}
+
+func TestParseHierarchy(t *testing.T) {
+	doc := strings.Join([]string{
+		"Move house +Home id:1",
+		"",
+		"Pack books id:2 parent:1",
+		"Buy boxes parent:2 +Errands",
+		"Lone task parent:99",
+		"Ping id:5 parent:6",
+		"Pong id:6 parent:5",
+		"Self id:7 parent:7",
+	}, "\n")
+
+	ws, err := Parse(strings.NewReader(doc), "Inbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, project := range ws.Projects {
+		names = append(names, project.Name)
+	}
+	if got := strings.Join(names, ","); got != "Home,Inbox,Errands" {
+		t.Errorf("projects = %s, want Home,Inbox,Errands", got)
+	}
+
+	tests := []struct {
+		title   string
+		parent  int64
+		project int64
+	}{
+		{"Move house", 0, 1},
+		{"Pack books", 1, 1},
+		{"Buy boxes", 2, 1}, // Subtasks follow their root's project
+		{"Lone task", 0, 2},
+		{"Ping", 0, 2}, // The cycle is broken at its first task
+		{"Pong", 5, 2},
+		{"Self", 0, 2},
+	}
+
+	if len(ws.Tasks) != len(tests) {
+		t.Fatalf("got %d tasks, want %d", len(ws.Tasks), len(tests))
+	}
+	for i, tt := range tests {
+		task := ws.Tasks[i]
+		var parent int64
+		if task.ParentTaskID.Valid {
+			parent = task.ParentTaskID.Int64
+		}
+		if task.Title != tt.title || parent != tt.parent || task.ProjectID.Int64 != tt.project {
+			t.Errorf("task %d = %q, parent %d, project %d, want %q, %d, %d",
+				task.ID, task.Title, parent, task.ProjectID.Int64, tt.title, tt.parent, tt.project)
+		}
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"line too long", "(A) " + strings.Repeat("a", 2*1024*1024)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.doc), "Inbox"); err == nil {
+				t.Error("Parse succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestWriteParse(t *testing.T) {
+	id := func(n int64) sql.NullInt64 { return sql.NullInt64{Int64: n, Valid: true} }
+	date := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+
+	projects := []models.Project{{ID: 1, Name: "Home Renovation"}, {ID: 2, Name: "Work"}}
+	tasks := []models.Task{
+		{ID: 1, ProjectID: id(1), Title: "Paint", Priority: models.PriorityHigh, CreatedAt: day(2026, time.October, 1), UpdatedAt: day(2026, time.October, 1), DueAt: date(day(2026, time.November, 1))},
+		{ID: 2, ProjectID: id(1), ParentTaskID: id(1), Title: "Buy rollers", Priority: models.PriorityUrgent, Completed: true, CreatedAt: day(2026, time.October, 2), UpdatedAt: day(2026, time.October, 9)},
+		{ID: 3, ProjectID: id(1), ParentTaskID: id(2), Title: "Compare prices", StartAt: date(day(2026, time.October, 3)), ScheduledFor: date(day(2026, time.October, 4))},
+		{ID: 4, ProjectID: id(2), Title: "Write report", Priority: models.PriorityLow},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, projects, tasks); err != nil {
+		t.Fatal(err)
+	}
+	ws, err := Parse(&buf, "Inbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ws.Projects) != 2 || ws.Projects[0].Name != "Home-Renovation" || ws.Projects[1].Name != "Work" {
+		t.Errorf("projects = %+v, want Home-Renovation and Work", ws.Projects)
+	}
+	if len(ws.Tasks) != len(tasks) {
+		t.Fatalf("got %d tasks, want %d", len(ws.Tasks), len(tasks))
+	}
+	for i, want := range tasks {
+		got := ws.Tasks[i]
+		if got != want {
+			t.Errorf("task %d = %+v, want %+v", i+1, got, want)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS update_drafts_timestamp;
+DROP INDEX IF EXISTS idx_drafts_created_at;
+DROP TABLE IF EXISTS drafts;
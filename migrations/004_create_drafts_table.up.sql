@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS drafts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    content TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index for listing drafts newest first
+CREATE INDEX IF NOT EXISTS idx_drafts_created_at ON drafts(created_at);
+
+-- Trigger to automatically update updated_at timestamp
+CREATE TRIGGER IF NOT EXISTS update_drafts_timestamp
+AFTER UPDATE ON drafts
+FOR EACH ROW
+BEGIN
+    UPDATE drafts SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
@@ -0,0 +1,36 @@
+DROP TRIGGER IF EXISTS update_notes_timestamp;
+CREATE TRIGGER IF NOT EXISTS update_notes_timestamp
+AFTER UPDATE ON notes
+FOR EACH ROW
+BEGIN
+    UPDATE notes SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+DROP TRIGGER IF EXISTS update_tasks_timestamp;
+CREATE TRIGGER IF NOT EXISTS update_tasks_timestamp
+AFTER UPDATE ON tasks
+FOR EACH ROW
+BEGIN
+    UPDATE tasks SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+DROP TRIGGER IF EXISTS update_projects_timestamp;
+CREATE TRIGGER IF NOT EXISTS update_projects_timestamp
+AFTER UPDATE ON projects
+FOR EACH ROW
+BEGIN
+    UPDATE projects SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- Trashed rows would come back to life without the column
+DELETE FROM notes WHERE deleted_at IS NOT NULL;
+DELETE FROM tasks WHERE deleted_at IS NOT NULL;
+DELETE FROM projects WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_notes_deleted_at;
+DROP INDEX IF EXISTS idx_tasks_deleted_at;
+DROP INDEX IF EXISTS idx_projects_deleted_at;
+
+ALTER TABLE notes DROP COLUMN deleted_at;
+ALTER TABLE tasks DROP COLUMN deleted_at;
+ALTER TABLE projects DROP COLUMN deleted_at;
@@ -0,0 +1,37 @@
+-- Soft delete: trashed rows keep their data until purged
+ALTER TABLE projects ADD COLUMN deleted_at DATETIME;
+ALTER TABLE tasks ADD COLUMN deleted_at DATETIME;
+ALTER TABLE notes ADD COLUMN deleted_at DATETIME;
+
+-- Indexes for listing and purging the trash
+CREATE INDEX IF NOT EXISTS idx_projects_deleted_at ON projects(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_notes_deleted_at ON notes(deleted_at);
+
+-- Moving rows to and from the trash is not an edit, so it keeps updated_at
+DROP TRIGGER IF EXISTS update_projects_timestamp;
+CREATE TRIGGER IF NOT EXISTS update_projects_timestamp
+AFTER UPDATE ON projects
+FOR EACH ROW
+WHEN NEW.deleted_at IS OLD.deleted_at
+BEGIN
+    UPDATE projects SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+DROP TRIGGER IF EXISTS update_tasks_timestamp;
+CREATE TRIGGER IF NOT EXISTS update_tasks_timestamp
+AFTER UPDATE ON tasks
+FOR EACH ROW
+WHEN NEW.deleted_at IS OLD.deleted_at
+BEGIN
+    UPDATE tasks SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+DROP TRIGGER IF EXISTS update_notes_timestamp;
+CREATE TRIGGER IF NOT EXISTS update_notes_timestamp
+AFTER UPDATE ON notes
+FOR EACH ROW
+WHEN NEW.deleted_at IS OLD.deleted_at
+BEGIN
+    UPDATE notes SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_tasks_scheduled_for;
+DROP INDEX IF EXISTS idx_tasks_due_at;
+
+ALTER TABLE tasks DROP COLUMN scheduled_for;
+ALTER TABLE tasks DROP COLUMN start_at;
+ALTER TABLE tasks DROP COLUMN due_at;
@@ -0,0 +1,9 @@
+-- Planning dates for tasks, stored as local wall-clock times: when the task
+-- is due, when work on it can start, and the day it is planned for
+ALTER TABLE tasks ADD COLUMN due_at DATETIME;
+ALTER TABLE tasks ADD COLUMN start_at DATETIME;
+ALTER TABLE tasks ADD COLUMN scheduled_for DATE;
+
+-- Indexes for finding overdue and scheduled tasks
+CREATE INDEX IF NOT EXISTS idx_tasks_due_at ON tasks(due_at);
+CREATE INDEX IF NOT EXISTS idx_tasks_scheduled_for ON tasks(scheduled_for);
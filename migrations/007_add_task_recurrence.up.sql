@@ -0,0 +1,4 @@
+-- Repeat rule of recurring tasks, a subset of iCalendar RRULE such as
+-- FREQ=WEEKLY;BYDAY=MO,WE. Completing a recurring task creates its next
+-- occurrence.
+ALTER TABLE tasks ADD COLUMN recurrence TEXT;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_task_dependencies_depends_on_id;
+DROP TABLE IF EXISTS task_dependencies;
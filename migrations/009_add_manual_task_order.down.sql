@@ -0,0 +1,22 @@
+DROP INDEX IF EXISTS idx_tasks_position;
+
+DROP TRIGGER IF EXISTS update_projects_timestamp;
+CREATE TRIGGER IF NOT EXISTS update_projects_timestamp
+AFTER UPDATE ON projects
+FOR EACH ROW
+WHEN NEW.deleted_at IS OLD.deleted_at
+BEGIN
+    UPDATE projects SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+DROP TRIGGER IF EXISTS update_tasks_timestamp;
+CREATE TRIGGER IF NOT EXISTS update_tasks_timestamp
+AFTER UPDATE ON tasks
+FOR EACH ROW
+WHEN NEW.deleted_at IS OLD.deleted_at
+BEGIN
+    UPDATE tasks SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+ALTER TABLE projects DROP COLUMN manual_order;
+ALTER TABLE tasks DROP COLUMN position;
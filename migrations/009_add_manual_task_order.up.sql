@@ -0,0 +1,39 @@
+-- Position of a task among its siblings, the tasks with the same project and
+-- parent. Positions are spaced apart so a task can usually move between two
+-- others without renumbering the rest.
+ALTER TABLE tasks ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+
+-- Whether a project lists its tasks in their manual order instead of by
+-- priority
+ALTER TABLE projects ADD COLUMN manual_order BOOLEAN NOT NULL DEFAULT 0;
+
+-- Reordering tasks or switching how they are ordered is not an edit, so it
+-- keeps updated_at
+DROP TRIGGER IF EXISTS update_projects_timestamp;
+CREATE TRIGGER IF NOT EXISTS update_projects_timestamp
+AFTER UPDATE ON projects
+FOR EACH ROW
+WHEN NEW.deleted_at IS OLD.deleted_at AND NEW.manual_order IS OLD.manual_order
+BEGIN
+    UPDATE projects SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+DROP TRIGGER IF EXISTS update_tasks_timestamp;
+CREATE TRIGGER IF NOT EXISTS update_tasks_timestamp
+AFTER UPDATE ON tasks
+FOR EACH ROW
+WHEN NEW.deleted_at IS OLD.deleted_at AND NEW.position IS OLD.position
+BEGIN
+    UPDATE tasks SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- Existing tasks start out in the order they were created
+UPDATE tasks SET position = 1024 * (
+    SELECT COUNT(*) FROM tasks AS sibling
+    WHERE sibling.project_id = tasks.project_id
+    AND sibling.parent_task_id IS tasks.parent_task_id
+    AND sibling.id <= tasks.id
+);
+
+-- Index for listing siblings in order
+CREATE INDEX IF NOT EXISTS idx_tasks_position ON tasks(project_id, parent_task_id, position);
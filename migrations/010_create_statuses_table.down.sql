@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_tasks_status_id;
+DROP INDEX IF EXISTS idx_statuses_project_id;
+
+ALTER TABLE tasks DROP COLUMN status_id;
+
+DROP TABLE IF EXISTS status_transitions;
+DROP TABLE IF EXISTS statuses;
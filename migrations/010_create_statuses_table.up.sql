@@ -0,0 +1,63 @@
+-- Workflow statuses of each project, in the order tasks move through them.
+-- Tasks in a done status are completed; new and reopened tasks start in the
+-- default one.
+CREATE TABLE IF NOT EXISTS statuses (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    is_done BOOLEAN NOT NULL DEFAULT 0,
+    is_default BOOLEAN NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE (project_id, name)
+);
+
+-- Moves allowed out of a status; a status without any allows every move
+CREATE TABLE IF NOT EXISTS status_transitions (
+    from_status_id INTEGER NOT NULL,
+    to_status_id INTEGER NOT NULL,
+    PRIMARY KEY (from_status_id, to_status_id),
+    FOREIGN KEY (from_status_id) REFERENCES statuses(id) ON DELETE CASCADE,
+    FOREIGN KEY (to_status_id) REFERENCES statuses(id) ON DELETE CASCADE,
+    CHECK (from_status_id != to_status_id)
+);
+
+-- Status of each task, one of its project's statuses. completed is kept in
+-- step with the status's is_done.
+ALTER TABLE tasks ADD COLUMN status_id INTEGER;
+
+-- Indexes for loading workflows and the tasks in a status
+CREATE INDEX IF NOT EXISTS idx_statuses_project_id ON statuses(project_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_status_id ON tasks(status_id);
+
+-- Every project starts with the default workflow
+INSERT INTO statuses (project_id, name, position, is_done, is_default)
+SELECT projects.id, defaults.name, defaults.position, defaults.is_done, defaults.is_default
+FROM projects, (
+    SELECT 'Backlog' AS name, 1 AS position, 0 AS is_done, 0 AS is_default
+    UNION ALL SELECT 'Todo', 2, 0, 1
+    UNION ALL SELECT 'In Progress', 3, 0, 0
+    UNION ALL SELECT 'In Review', 4, 0, 0
+    UNION ALL SELECT 'Blocked', 5, 0, 0
+    UNION ALL SELECT 'Done', 6, 1, 0
+) AS defaults
+ORDER BY projects.id, defaults.position;
+
+-- Completed tasks are Done and the others Todo. Setting the status is not an
+-- edit, so the trigger is left out while it runs.
+DROP TRIGGER IF EXISTS update_tasks_timestamp;
+
+UPDATE tasks SET status_id = (
+    SELECT id FROM statuses
+    WHERE statuses.project_id = tasks.project_id
+    AND statuses.name = CASE WHEN tasks.completed THEN 'Done' ELSE 'Todo' END
+);
+
+CREATE TRIGGER IF NOT EXISTS update_tasks_timestamp
+AFTER UPDATE ON tasks
+FOR EACH ROW
+WHEN NEW.deleted_at IS OLD.deleted_at AND NEW.position IS OLD.position
+BEGIN
+    UPDATE tasks SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
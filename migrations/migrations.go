@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL migration files so the binary can run
+// them regardless of the working directory it is started from.
+package migrations
+
+import "embed"
+
+// FS holds every *.sql migration in this directory
+//
+//go:embed *.sql
+var FS embed.FS